@@ -0,0 +1,111 @@
+package sbanken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidAccountNumber are returned when an account number is not 11 digits.
+	ErrInvalidAccountNumber = errors.New("invalid account number")
+	// ErrAccountNumberCheckDigit are returned when the MOD11 check digit of an account number does not match.
+	ErrAccountNumberCheckDigit = errors.New("invalid account number check digit")
+)
+
+// accountNumberWeights are the MOD11 weights of the first ten digits of an account number.
+var accountNumberWeights = [10]int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+
+// AccountNumber represents a Norwegian bank account number, such as "9710.41.33219".
+// The first four digits are the bank registration number, and the last digit is a MOD11 check digit.
+//
+// Parsed account numbers hold the 11 digits without separators. Values from the API that are
+// not valid account numbers, such as foreign account numbers, are kept as they are, so
+// decoding never fails on them. Use Valid to check a value.
+type AccountNumber string
+
+// ParseAccountNumber parses an 11-digit account number, with or without dots or spaces
+// as separators, and validates the check digit.
+func ParseAccountNumber(s string) (AccountNumber, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '.' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.TrimSpace(s))
+
+	if len(digits) != 11 || strings.Trim(digits, "0123456789") != "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAccountNumber, s)
+	}
+
+	if check, ok := mod11(digits[:10], accountNumberWeights[:]); !ok || check != int(digits[10]-'0') {
+		return "", fmt.Errorf("%w: %q", ErrAccountNumberCheckDigit, s)
+	}
+
+	return AccountNumber(digits), nil
+}
+
+// mod11 returns the MOD11 check digit of the digits with the given weights. It reports false
+// when the remainder gives a check digit of 10, which can not be used.
+func mod11(digits string, weights []int) (int, bool) {
+	sum := 0
+	for i := range digits {
+		sum += int(digits[i]-'0') * weights[i]
+	}
+
+	check := (11 - sum%11) % 11
+
+	return check, check != 10
+}
+
+// Valid reports whether the account number is 11 digits with a valid check digit.
+func (a AccountNumber) Valid() bool {
+	_, err := ParseAccountNumber(string(a))
+	return err == nil
+}
+
+// RegistrationNumber returns the bank registration number, the first four digits of the
+// account number, such as "9710" for Sbanken. It is empty if the account number is not valid.
+func (a AccountNumber) RegistrationNumber() string {
+	digits, err := ParseAccountNumber(string(a))
+	if err != nil {
+		return ""
+	}
+
+	return string(digits[:4])
+}
+
+// String returns the account number formatted as XXXX.XX.XXXXX. Account numbers that are not
+// valid are returned as they are.
+func (a AccountNumber) String() string {
+	digits, err := ParseAccountNumber(string(a))
+	if err != nil {
+		return string(a)
+	}
+
+	return fmt.Sprintf("%s.%s.%s", digits[:4], digits[4:6], digits[6:])
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Valid account numbers are normalized to 11 digits,
+// and other strings are kept as they are.
+func (a *AccountNumber) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if parsed, err := ParseAccountNumber(s); err == nil {
+		*a = parsed
+		return nil
+	}
+
+	*a = AccountNumber(s)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package sbanken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseAccountNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		exp    AccountNumber
+		expErr error
+	}{
+		{name: "should parse digits", in: "97104133219", exp: "97104133219"},
+		{name: "should parse dotted format", in: "9710.41.33219", exp: "97104133219"},
+		{name: "should parse spaced format", in: " 9710 41 33219 ", exp: "97104133219"},
+		{name: "should parse check digit zero", in: "00000000000", exp: "00000000000"},
+		{name: "should fail on wrong check digit", in: "97104133218", expErr: ErrAccountNumberCheckDigit},
+		{name: "should fail on unusable check digit", in: "00000200000", expErr: ErrAccountNumberCheckDigit},
+		{name: "should fail on too few digits", in: "9710413321", expErr: ErrInvalidAccountNumber},
+		{name: "should fail on too many digits", in: "971041332190", expErr: ErrInvalidAccountNumber},
+		{name: "should fail on letters", in: "9710A133219", expErr: ErrInvalidAccountNumber},
+		{name: "should fail on other separators", in: "9710-41-33219", expErr: ErrInvalidAccountNumber},
+		{name: "should fail on empty string", in: "", expErr: ErrInvalidAccountNumber},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := ParseAccountNumber(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if a != tc.exp {
+				t.Errorf("unexpected account number: got %q, exp %q", a, tc.exp)
+			}
+		})
+	}
+}
+
+func TestAccountNumberFormat(t *testing.T) {
+	tests := []struct {
+		name            string
+		a               AccountNumber
+		expString       string
+		expRegistration string
+		expValid        bool
+	}{
+		{name: "should format valid account number", a: "97104133219", expString: "9710.41.33219", expRegistration: "9710", expValid: true},
+		{name: "should format unnormalized account number", a: "1234 56 78903", expString: "1234.56.78903", expRegistration: "1234", expValid: true},
+		{name: "should keep invalid account number", a: "987654321", expString: "987654321"},
+		{name: "should keep foreign account number", a: "DE89370400440532013000", expString: "DE89370400440532013000"},
+		{name: "should handle empty account number"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.String(); got != tc.expString {
+				t.Errorf("unexpected string: got %q, exp %q", got, tc.expString)
+			}
+
+			if got := tc.a.RegistrationNumber(); got != tc.expRegistration {
+				t.Errorf("unexpected registration number: got %q, exp %q", got, tc.expRegistration)
+			}
+
+			if got := tc.a.Valid(); got != tc.expValid {
+				t.Errorf("unexpected valid: got %t, exp %t", got, tc.expValid)
+			}
+		})
+	}
+}
+
+func TestAccountNumberJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		exp     AccountNumber
+		expJSON string
+		expErr  bool
+	}{
+		{name: "should normalize valid account number", in: `"9710.41.33219"`, exp: "97104133219", expJSON: `"97104133219"`},
+		{name: "should keep invalid account number", in: `"123141423"`, exp: "123141423", expJSON: `"123141423"`},
+		{name: "should handle null", in: `null`, expJSON: `""`},
+		{name: "should fail on number", in: `97104133219`, expErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var a AccountNumber
+
+			err := json.Unmarshal([]byte(tc.in), &a)
+			if (err != nil) != tc.expErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.expErr {
+				return
+			}
+
+			if a != tc.exp {
+				t.Errorf("unexpected account number: got %q, exp %q", a, tc.exp)
+			}
+
+			b, err := json.Marshal(a)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.expJSON {
+				t.Errorf("unexpected JSON: got %s, exp %s", b, tc.expJSON)
+			}
+		})
+	}
+}
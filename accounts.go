@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -12,48 +11,50 @@ import (
 // Account represents an account.
 // Sbanken API documentation: https://publicapi.sbanken.no/openapi/apibeta/index.html#/Accounts
 type Account struct {
-	ID              string  `json:"accountId"`
-	Name            string  `json:"name"`
-	Type            string  `json:"accountType"`
-	Number          string  `json:"accountNumber"`
-	OwnerCustomerID string  `json:"ownerCustomerId"`
-	Available       float32 `json:"available"`
-	Balance         float32 `json:"balance"`
-	CreditLimit     float32 `json:"creditLimit"`
+	ID              string        `json:"accountId"`
+	Name            string        `json:"name"`
+	Type            string        `json:"accountType"`
+	Number          AccountNumber `json:"accountNumber"`
+	OwnerCustomerID string        `json:"ownerCustomerId"`
+	Available       Money         `json:"available"`
+	Balance         Money         `json:"balance"`
+	CreditLimit     Money         `json:"creditLimit"`
+}
+
+// AccountPage represents a list of accounts, together with the total number of available items and the trace ID of the response.
+type AccountPage struct {
+	TraceID        string
+	Items          []Account
+	AvailableItems int
 }
 
 // ListAccounts lists the accounts.
 func (c *Client) ListAccounts(ctx context.Context) ([]Account, error) {
-	url := fmt.Sprintf("%s/v1/Accounts", c.bankBaseURL)
-
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
-		Method: http.MethodGet,
-		URL:    url,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
+	page, err := c.ListAccountsPage(ctx)
+	if page == nil {
+		return nil, err
 	}
 
-	data := struct {
-		Accounts []Account `json:"items"`
-		transport.HTTPResponse
-	}{}
+	return page.Items, err
+}
 
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Accounts, fmt.Errorf("Unmarshal: %w", err)
-	}
+// ListAccountsPage is like ListAccounts, but also returns the number of available items and the trace ID.
+func (c *Client) ListAccountsPage(ctx context.Context) (*AccountPage, error) {
+	url := fmt.Sprintf("%s/%s/Accounts", c.bankBaseURL, c.apiVersion)
 
-	if data.IsError || sc != http.StatusOK {
-		return nil, &Error{
-			"ListAccounts",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	data, err := do[Account](ctx, c, "ListAccounts", &transport.HTTPRequest{
+		Method: http.MethodGet,
+		URL:    url,
+	})
+	if data == nil {
+		return nil, err
 	}
 
-	return data.Accounts, nil
+	return &AccountPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
 
 // ReadAccount reads an account. The accountID are required.
@@ -62,34 +63,15 @@ func (c *Client) ReadAccount(ctx context.Context, accountID string) (Account, er
 		return Account{}, ErrMissingAccountID
 	}
 
-	url := fmt.Sprintf("%s/v1/Accounts/%s", c.bankBaseURL, accountID)
+	url := fmt.Sprintf("%s/%s/Accounts/%s", c.bankBaseURL, c.apiVersion, accountID)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Account](ctx, c, "ReadAccount", &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return Account{}, fmt.Errorf("request: %w", err)
-	}
-
-	data := struct {
-		Account Account `json:"item"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Account, fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return data.Account, &Error{
-			"ReadAccount",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	if data == nil {
+		return Account{}, err
 	}
 
-	return data.Account, nil
+	return data.Item, err
 }
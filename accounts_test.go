@@ -16,9 +16,9 @@ var testAccount = Account{
 	Type:            "Account",
 	Number:          "123456789",
 	OwnerCustomerID: "987654321",
-	Available:       123.45,
-	Balance:         123.45,
-	CreditLimit:     0.0,
+	Available:       12345,
+	Balance:         12345,
+	CreditLimit:     0,
 }
 
 func testListAccountsEndpointResponse(behavior string) ([]byte, int, error) {
@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -12,48 +11,50 @@ import (
 // Card represents a card.
 // Sbanken API documentation: https://publicapi.sbanken.no/openapi/apibeta/index.html#/Cards/Cards_List
 type Card struct {
-	ID            string `json:"cardId"`
-	Number        string `json:"cardNumber"`
-	ExpiryDate    string `json:"expiryDate"`
-	Status        string `json:"status"`
-	Type          string `json:"cardType"`
-	ProductCode   string `json:"productCode"`
-	AccountNumber string `json:"accountNumber"`
-	AccountOwner  string `json:"accountOwner"`
-	CustomerID    string `json:"customerId"`
-	VersionNumber int    `json:"cardVersionNumber"`
+	ID            string        `json:"cardId"`
+	Number        string        `json:"cardNumber"`
+	ExpiryDate    Date          `json:"expiryDate"`
+	Status        string        `json:"status"`
+	Type          string        `json:"cardType"`
+	ProductCode   string        `json:"productCode"`
+	AccountNumber AccountNumber `json:"accountNumber"`
+	AccountOwner  string        `json:"accountOwner"`
+	CustomerID    string        `json:"customerId"`
+	VersionNumber int           `json:"cardVersionNumber"`
+}
+
+// CardPage represents a list of cards, together with the total number of available items and the trace ID of the response.
+type CardPage struct {
+	TraceID        string
+	Items          []Card
+	AvailableItems int
 }
 
 // ListCards lists the cards.
 func (c *Client) ListCards(ctx context.Context) ([]Card, error) {
-	url := fmt.Sprintf("%s/v1/Cards", c.bankBaseURL)
-
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
-		Method: http.MethodGet,
-		URL:    url,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
+	page, err := c.ListCardsPage(ctx)
+	if page == nil {
+		return nil, err
 	}
 
-	data := struct {
-		Cards []Card `json:"items"`
-		transport.HTTPResponse
-	}{}
+	return page.Items, err
+}
 
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Cards, fmt.Errorf("Unmarshal: %w", err)
-	}
+// ListCardsPage is like ListCards, but also returns the number of available items and the trace ID.
+func (c *Client) ListCardsPage(ctx context.Context) (*CardPage, error) {
+	url := fmt.Sprintf("%s/%s/Cards", c.bankBaseURL, c.apiVersion)
 
-	if data.IsError || sc != http.StatusOK {
-		return data.Cards, &Error{
-			"ListCards",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	data, err := do[Card](ctx, c, "ListCards", &transport.HTTPRequest{
+		Method: http.MethodGet,
+		URL:    url,
+	})
+	if data == nil {
+		return nil, err
 	}
 
-	return data.Cards, nil
+	return &CardPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
-	"time"
 
 	"github.com/engvik/sbanken-go/internal/transport"
 )
@@ -14,7 +13,7 @@ import (
 var testCard = Card{
 	ID:            "test-card",
 	Number:        "123456789",
-	ExpiryDate:    time.Now().String(),
+	ExpiryDate:    mustParseDate("2024-05-31T00:00:00"),
 	Status:        "status",
 	Type:          "type",
 	ProductCode:   "code",
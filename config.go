@@ -1,31 +1,183 @@
 package sbanken
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Sbanken bank API.
+	DefaultBaseURL = "https://publicapi.sbanken.no/apibeta/api"
+	// DefaultAuthURL is the token endpoint of the Sbanken identity server.
+	DefaultAuthURL = "https://auth.sbanken.no/identityserver/connect/token"
+	// DefaultAPIVersion is the API version path segment used in requests.
+	DefaultAPIVersion = "v1"
+)
+
+// Logger is the interface used for logging, satisfied by *log.Logger.
+type Logger = transport.Logger
 
 // Config represents Sbanken client config.
 type Config struct {
-	// ClientID is required.
+	// ClientID is required, unless TokenSource is set.
 	ClientID string
-	// ClientSecret is required.
+	// ClientSecret is required, unless TokenSource is set.
 	ClientSecret string
 	// CustomerID is deprecated.
 	CustomerID string
 	// UserAgent is for optionally setting a custom user agent.
 	UserAgent string
-	skipAuth  bool
+	// BaseURL is for optionally overriding the bank API base URL, e.g. for test servers. Defaults to DefaultBaseURL.
+	BaseURL string
+	// AuthURL is for optionally overriding the identity server token URL. Defaults to DefaultAuthURL.
+	AuthURL string
+	// APIVersion is for optionally overriding the API version path segment. Defaults to DefaultAPIVersion.
+	APIVersion string
+	// TokenSource is for optionally supplying access tokens from another source than the client credentials grant,
+	// such as a static token or a token cached between runs. ClientID, ClientSecret and AuthURL are not used when it is set.
+	TokenSource TokenSource
+	// TokenRefreshSkew is for optionally setting how long before expiry the access token is refreshed.
+	// Defaults to 30 seconds. It is passed to token sources implementing SkewTokenSource, such as CachingTokenSource.
+	TokenRefreshSkew time.Duration
+	// RetryPolicy is for optionally retrying failed requests. Requests are not retried if nil.
+	RetryPolicy *RetryPolicy
+	// RateLimit is for optionally limiting the number of requests per second. Requests are not limited if zero.
+	// Clients using the same ClientID, RateLimit and RateBurst share the same limiter, so the limit applies to their
+	// combined requests. Clients without a ClientID, e.g. with a TokenSource, are not limited together. Use RateLimiter
+	// to share a limiter between them.
+	RateLimit float64
+	// RateBurst is the maximum number of requests allowed in a burst when RateLimit is set. Defaults to 1.
+	RateBurst int
+	// RateLimiter is for optionally sharing a rate limiter between clients. It takes precedence over RateLimit.
+	RateLimiter *RateLimiter
+	// MaxBodySize is for optionally limiting the size of response bodies in bytes. Defaults to 10 MiB.
+	MaxBodySize int64
+	// Middleware is for optionally wrapping requests, e.g. for logging, metrics or header injection.
+	// The first middleware is the outermost.
+	Middleware []Middleware
+	// Logger is for optionally logging warnings and retried requests. Warnings are logged
+	// with the standard logger if nil, and retries are not logged.
+	Logger Logger
+	// LazyAuth is for optionally deferring authorization until the first request, so that NewClient
+	// does not fail when the identity server is unavailable. Use Ping to verify the credentials.
+	LazyAuth bool
 }
 
 func (c *Config) validate() error {
-	if c.ClientID == "" {
-		return ErrMissingClientID
+	if c.TokenSource == nil {
+		if c.ClientID == "" {
+			return ErrMissingClientID
+		}
+
+		if c.ClientSecret == "" {
+			return ErrMissingClientSecret
+		}
 	}
 
-	if c.ClientSecret == "" {
-		return ErrMissingClientSecret
+	if c.BaseURL != "" {
+		if err := validateURL(c.BaseURL); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidBaseURL, err)
+		}
+	}
+
+	if c.AuthURL != "" {
+		if err := validateURL(c.AuthURL); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAuthURL, err)
+		}
+	}
+
+	if c.APIVersion != "" && strings.ContainsAny(c.APIVersion, "/?#") {
+		return ErrInvalidAPIVersion
+	}
+
+	if c.TokenRefreshSkew < 0 {
+		return ErrInvalidTokenRefreshSkew
+	}
+
+	if c.RetryPolicy != nil && (c.RetryPolicy.Jitter < 0 || c.RetryPolicy.Jitter > 1) {
+		return ErrInvalidRetryJitter
+	}
+
+	if c.RateLimit < 0 || c.RateBurst < 0 {
+		return ErrInvalidRateLimit
+	}
+
+	if c.MaxBodySize < 0 {
+		return ErrInvalidMaxBodySize
 	}
 
 	if c.CustomerID != "" {
-		log.Println("Customer ID is deprecated.")
+		c.logger().Printf("Customer ID is deprecated.")
+	}
+
+	return nil
+}
+
+func (c *Config) logger() Logger {
+	if c.Logger == nil {
+		return log.Default()
+	}
+
+	return c.Logger
+}
+
+func (c *Config) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+func (c *Config) authURL() string {
+	if c.AuthURL == "" {
+		return DefaultAuthURL
+	}
+
+	return c.AuthURL
+}
+
+func (c *Config) apiVersion() string {
+	if c.APIVersion == "" {
+		return DefaultAPIVersion
+	}
+
+	return c.APIVersion
+}
+
+func (c *Config) rateLimiter() *RateLimiter {
+	if c.RateLimiter != nil {
+		return c.RateLimiter
+	}
+
+	if c.RateLimit == 0 {
+		return nil
+	}
+
+	return transport.SharedRateLimiter(c.ClientID, c.RateLimit, c.RateBurst)
+}
+
+func validateURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", parsed.Scheme)
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("missing host in %q", u)
+	}
+
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("unexpected query or fragment in %q", u)
 	}
 
 	return nil
@@ -1,6 +1,9 @@
 package sbanken
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestValidate(t *testing.T) {
 	tests := []struct {
@@ -23,13 +26,109 @@ func TestValidate(t *testing.T) {
 			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret"},
 			exp:  nil,
 		},
+		{
+			name: "should not validate when MaxBodySize is negative",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", MaxBodySize: -1},
+			exp:  ErrInvalidMaxBodySize,
+		},
+		{
+			name: "should validate without credentials when TokenSource is set",
+			cfg:  &Config{TokenSource: StaticTokenSource("token")},
+			exp:  nil,
+		},
+		{
+			name: "should validate custom URLs and API version",
+			cfg: &Config{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				BaseURL:      "http://127.0.0.1:8080/api/",
+				AuthURL:      "http://127.0.0.1:8080/connect/token",
+				APIVersion:   "v2",
+			},
+			exp: nil,
+		},
+		{
+			name: "should not validate when BaseURL has no scheme",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", BaseURL: "publicapi.sbanken.no/api"},
+			exp:  ErrInvalidBaseURL,
+		},
+		{
+			name: "should not validate when BaseURL has a query",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", BaseURL: "https://example.com/api?x=1"},
+			exp:  ErrInvalidBaseURL,
+		},
+		{
+			name: "should not validate when AuthURL has unsupported scheme",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", AuthURL: "ftp://example.com/token"},
+			exp:  ErrInvalidAuthURL,
+		},
+		{
+			name: "should not validate when retry jitter is out of range",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", RetryPolicy: &RetryPolicy{Jitter: 1.5}},
+			exp:  ErrInvalidRetryJitter,
+		},
+		{
+			name: "should not validate when rate limit is negative",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", RateLimit: -1},
+			exp:  ErrInvalidRateLimit,
+		},
+		{
+			name: "should not validate when APIVersion is not a path segment",
+			cfg:  &Config{ClientID: "client-id", ClientSecret: "client-secret", APIVersion: "v1/Accounts"},
+			exp:  ErrInvalidAPIVersion,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			if err := tc.cfg.validate(); err != tc.exp {
+			if err := tc.cfg.validate(); !errors.Is(err, tc.exp) {
 				t.Errorf("unexpected result: got %s, exp %s", err, tc.exp)
 			}
 		})
 	}
 }
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("should not limit by default", func(t *testing.T) {
+		cfg := &Config{ClientID: "client-id"}
+		if l := cfg.rateLimiter(); l != nil {
+			t.Errorf("unexpected rate limiter: %v", l)
+		}
+	})
+
+	t.Run("should share rate limiter between clients with same client ID", func(t *testing.T) {
+		a := &Config{ClientID: "rate-limited-client-id", RateLimit: 5}
+		b := &Config{ClientID: "rate-limited-client-id", RateLimit: 5}
+
+		if a.rateLimiter() != b.rateLimiter() {
+			t.Error("expected rate limiter to be shared")
+		}
+	})
+
+	t.Run("should not share rate limiter between clients with other limits", func(t *testing.T) {
+		a := &Config{ClientID: "rate-limited-client-id", RateLimit: 5}
+		b := &Config{ClientID: "rate-limited-client-id", RateLimit: 10}
+
+		if a.rateLimiter() == b.rateLimiter() {
+			t.Error("expected separate rate limiters")
+		}
+	})
+
+	t.Run("should not share rate limiter between clients without client ID", func(t *testing.T) {
+		a := &Config{RateLimit: 5}
+		b := &Config{RateLimit: 5}
+
+		if a.rateLimiter() == b.rateLimiter() {
+			t.Error("expected separate rate limiters")
+		}
+	})
+
+	t.Run("should prefer explicit rate limiter", func(t *testing.T) {
+		l := NewRateLimiter(5, 1)
+		cfg := &Config{ClientID: "rate-limited-client-id", RateLimit: 5, RateLimiter: l}
+
+		if cfg.rateLimiter() != l {
+			t.Error("expected explicit rate limiter")
+		}
+	})
+}
@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -16,7 +15,7 @@ type Customer struct {
 	FirstName     string        `json:"firstName"`
 	LastName      string        `json:"lastName"`
 	EmailAddress  string        `json:"emailAddress"`
-	DateOfBirth   string        `json:"dateOfBirth"`
+	DateOfBirth   Date          `json:"dateOfBirth"`
 	PostalAddress Address       `json:"postalAddress"`
 	StreetAddress Address       `json:"streetAddress"`
 	PhoneNumbers  []PhoneNumber `json:"phoneNumbers"`
@@ -30,34 +29,16 @@ type PhoneNumber struct {
 
 // GetCustomer lists customer information.
 func (c *Client) GetCustomer(ctx context.Context) (Customer, error) {
-	url := fmt.Sprintf("%s/v1/Customers", c.bankBaseURL)
+	url := fmt.Sprintf("%s/%s/Customers", c.bankBaseURL, c.apiVersion)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
-		Method: http.MethodGet,
-		URL:    url,
+	data, err := do[Customer](ctx, c, "Customers", &transport.HTTPRequest{
+		Operation: "GetCustomer",
+		Method:    http.MethodGet,
+		URL:       url,
 	})
-	if err != nil {
-		return Customer{}, fmt.Errorf("request: %w", err)
+	if data == nil {
+		return Customer{}, err
 	}
 
-	data := struct {
-		Customer `json:"item"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Customer, fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return Customer{}, &Error{
-			"Customers",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
-	}
-
-	return data.Customer, nil
+	return data.Item, err
 }
@@ -15,7 +15,7 @@ var testCustomer = Customer{
 	FirstName:    "Testy",
 	LastName:     "Tester",
 	EmailAddress: "testy@tester.com",
-	DateOfBirth:  "2021-01-31T10:05:54.590Z",
+	DateOfBirth:  mustParseDate("2021-01-31T10:05:54.590Z"),
 	PostalAddress: Address{
 		AddressLine1: "Tester street 1",
 	},
@@ -0,0 +1,208 @@
+package sbanken
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database so Europe/Oslo is available on systems without one.
+	_ "time/tzdata"
+)
+
+// ErrInvalidDate are returned when a date can not be parsed.
+var ErrInvalidDate = errors.New("invalid date")
+
+// dateOnlyLayout is the layout of date-only values.
+const dateOnlyLayout = "2006-01-02"
+
+var osloLocation = loadOsloLocation()
+
+func loadOsloLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Date represents a date or timestamp returned by the API.
+//
+// The API returns dates both as date-only values ("2021-01-31"), as local
+// timestamps without offset ("2021-01-31T00:00:00") and as timestamps with
+// offset ("2021-01-31T10:05:54.590Z"). Values without offset are interpreted
+// in the Europe/Oslo zone, and values with offset are converted to it, so that
+// e.g. Day returns the day in Norway. Date marshals back to JSON using the same
+// layout and offset it was parsed from.
+type Date struct {
+	time.Time
+	layout string
+	// zone is the location of the offset on the wire, or nil for Europe/Oslo.
+	zone *time.Location
+}
+
+// ParseDate parses a date in any of the formats returned by the API.
+func ParseDate(s string) (Date, error) {
+	layout, hasZone, err := dateLayout(s)
+	if err != nil {
+		return Date{}, err
+	}
+
+	if hasZone {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+		}
+
+		return Date{Time: t.In(osloLocation), layout: layout, zone: t.Location()}, nil
+	}
+
+	t, err := time.ParseInLocation(layout, s, osloLocation)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+
+	return Date{Time: t, layout: layout}, nil
+}
+
+// NewDate returns a timestamp Date for t in the Europe/Oslo zone. It marshals as RFC 3339
+// with the offset of t.
+func NewDate(t time.Time) Date {
+	return Date{Time: t.In(osloLocation), layout: time.RFC3339, zone: t.Location()}
+}
+
+// DateOf returns a date-only Date for the given day in the Europe/Oslo zone.
+func DateOf(year int, month time.Month, day int) Date {
+	return Date{
+		Time:   time.Date(year, month, day, 0, 0, 0, 0, osloLocation),
+		layout: dateOnlyLayout,
+	}
+}
+
+// IsDateOnly reports whether the date carries no time of day. This is the case
+// for date-only values, and for values without offset at exactly midnight, which
+// is how the API represents days such as accounting and due dates.
+func (d Date) IsDateOnly() bool {
+	if d.layout == "" || d.layout == dateOnlyLayout {
+		return true
+	}
+
+	if strings.Contains(d.layout, "Z07") {
+		return false
+	}
+
+	h, m, s := d.Clock()
+
+	return h == 0 && m == 0 && s == 0 && d.Nanosecond() == 0
+}
+
+// String returns the date in the layout it was parsed from.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return d.wireTime().Format(d.wireLayout())
+}
+
+// MarshalJSON implements json.Marshaler. Zero dates are encoded as null.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + d.wireTime().Format(d.wireLayout()) + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Empty strings and null are decoded as the zero Date.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" || s == `""` {
+		*d = Date{}
+		return nil
+	}
+
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return fmt.Errorf("%w: %s", ErrInvalidDate, s)
+	}
+
+	v, err := ParseDate(s[1 : len(s)-1])
+	if err != nil {
+		return err
+	}
+
+	*d = v
+
+	return nil
+}
+
+// wireTime returns the time in the zone it was parsed from.
+func (d Date) wireTime() time.Time {
+	if d.zone == nil {
+		return d.Time
+	}
+
+	return d.In(d.zone)
+}
+
+func (d Date) wireLayout() string {
+	if d.layout == "" {
+		return time.RFC3339
+	}
+
+	return d.layout
+}
+
+// dateLayout derives the exact layout of s, so that formatting with it reproduces the wire format.
+func dateLayout(s string) (string, bool, error) {
+	if len(s) < len(dateOnlyLayout) {
+		return "", false, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+
+	if len(s) == len(dateOnlyLayout) {
+		return dateOnlyLayout, false, nil
+	}
+
+	rest := s[len(dateOnlyLayout):]
+	if rest[0] != 'T' && rest[0] != ' ' {
+		return "", false, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+
+	layout := dateOnlyLayout + rest[:1] + "15:04"
+	rest = rest[1:]
+
+	if len(rest) < len("15:04") {
+		return "", false, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+
+	rest = rest[len("15:04"):]
+
+	if strings.HasPrefix(rest, ":") {
+		layout += ":05"
+		if len(rest) < len(":05") {
+			return "", false, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+		}
+
+		rest = rest[len(":05"):]
+	}
+
+	if strings.HasPrefix(rest, ".") {
+		n := 1
+		for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+
+		layout += "." + strings.Repeat("0", n-1)
+		rest = rest[n:]
+	}
+
+	switch {
+	case rest == "":
+		return layout, false, nil
+	case rest == "Z" || strings.Contains(rest, ":"):
+		return layout + "Z07:00", true, nil
+	default:
+		return layout + "Z0700", true, nil
+	}
+}
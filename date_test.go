@@ -0,0 +1,182 @@
+package sbanken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustParseDate(s string) Date {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		exp         time.Time
+		expOffset   int
+		expDateOnly bool
+		expErr      error
+	}{
+		{
+			name:        "should parse date-only value",
+			in:          "2021-01-31",
+			exp:         time.Date(2021, time.January, 31, 0, 0, 0, 0, osloLocation),
+			expOffset:   3600,
+			expDateOnly: true,
+		},
+		{
+			name:        "should parse local midnight as date",
+			in:          "2021-01-31T00:00:00",
+			exp:         time.Date(2021, time.January, 31, 0, 0, 0, 0, osloLocation),
+			expOffset:   3600,
+			expDateOnly: true,
+		},
+		{
+			name:      "should parse local timestamp in Europe/Oslo",
+			in:        "2021-07-01T13:37:00",
+			exp:       time.Date(2021, time.July, 1, 13, 37, 0, 0, osloLocation),
+			expOffset: 7200,
+		},
+		{
+			name:      "should parse UTC timestamp with fraction",
+			in:        "2021-01-31T10:05:54.590Z",
+			exp:       time.Date(2021, time.January, 31, 10, 5, 54, 590000000, time.UTC),
+			expOffset: 3600,
+		},
+		{
+			name:      "should parse UTC timestamp into the day in Europe/Oslo",
+			in:        "2021-01-31T23:30:00Z",
+			exp:       time.Date(2021, time.February, 1, 0, 30, 0, 0, osloLocation),
+			expOffset: 3600,
+		},
+		{
+			name:      "should parse space separated timestamp",
+			in:        "2021-01-31 10:05:00",
+			exp:       time.Date(2021, time.January, 31, 10, 5, 0, 0, osloLocation),
+			expOffset: 3600,
+		},
+		{
+			name:        "should parse space separated midnight as date",
+			in:          "2021-01-31 00:00:00",
+			exp:         time.Date(2021, time.January, 31, 0, 0, 0, 0, osloLocation),
+			expOffset:   3600,
+			expDateOnly: true,
+		},
+		{
+			name:      "should parse timestamp with offset",
+			in:        "2021-01-31T00:00:00+01:00",
+			exp:       time.Date(2021, time.January, 31, 0, 0, 0, 0, osloLocation),
+			expOffset: 3600,
+		},
+		{
+			name:      "should parse timestamp with compact offset",
+			in:        "2021-01-31T00:00:00+0100",
+			exp:       time.Date(2021, time.January, 31, 0, 0, 0, 0, osloLocation),
+			expOffset: 3600,
+		},
+		{
+			name:   "should fail on garbage",
+			in:     "31.01.2021",
+			expErr: ErrInvalidDate,
+		},
+		{
+			name:   "should fail on truncated value",
+			in:     "2021-01",
+			expErr: ErrInvalidDate,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := ParseDate(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !d.Equal(tc.exp) {
+				t.Errorf("unexpected time: got %s, exp %s", d.Time, tc.exp)
+			}
+
+			if _, offset := d.Zone(); offset != tc.expOffset {
+				t.Errorf("unexpected offset: got %d, exp %d", offset, tc.expOffset)
+			}
+
+			if d.Location() != osloLocation {
+				t.Errorf("unexpected location: got %s", d.Location())
+			}
+
+			if y, m, day := tc.exp.In(osloLocation).Date(); d.Year() != y || d.Month() != m || d.Day() != day {
+				t.Errorf("unexpected day: got %d-%d-%d", d.Year(), d.Month(), d.Day())
+			}
+
+			if d.IsDateOnly() != tc.expDateOnly {
+				t.Errorf("unexpected date-only: got %t, exp %t", d.IsDateOnly(), tc.expDateOnly)
+			}
+		})
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{name: "should round-trip date-only value", in: `{"d":"2021-01-31"}`, exp: `{"d":"2021-01-31"}`},
+		{name: "should round-trip local timestamp", in: `{"d":"2021-01-31T00:00:00"}`, exp: `{"d":"2021-01-31T00:00:00"}`},
+		{name: "should round-trip fraction", in: `{"d":"2021-01-31T13:37:00.120"}`, exp: `{"d":"2021-01-31T13:37:00.120"}`},
+		{name: "should round-trip offset", in: `{"d":"2021-01-31T13:37:00+01:00"}`, exp: `{"d":"2021-01-31T13:37:00+01:00"}`},
+		{name: "should round-trip UTC", in: `{"d":"2021-01-31T10:05:54.590Z"}`, exp: `{"d":"2021-01-31T10:05:54.590Z"}`},
+		{name: "should round-trip summer offset", in: `{"d":"2021-07-01T13:37:00+02:00"}`, exp: `{"d":"2021-07-01T13:37:00+02:00"}`},
+		{name: "should round-trip offset other than Europe/Oslo", in: `{"d":"2021-07-01T13:37:00-04:00"}`, exp: `{"d":"2021-07-01T13:37:00-04:00"}`},
+		{name: "should round-trip space separated timestamp", in: `{"d":"2021-01-31 10:05:00"}`, exp: `{"d":"2021-01-31 10:05:00"}`},
+		{name: "should round-trip UTC across midnight", in: `{"d":"2021-01-31T23:30:00Z"}`, exp: `{"d":"2021-01-31T23:30:00Z"}`},
+		{name: "should decode null as zero", in: `{"d":null}`, exp: `{"d":null}`},
+		{name: "should decode empty string as zero", in: `{"d":""}`, exp: `{"d":null}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var v struct {
+				D Date `json:"d"`
+			}
+
+			if err := json.Unmarshal([]byte(tc.in), &v); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.exp {
+				t.Errorf("unexpected json: got %s, exp %s", b, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDateConstructors(t *testing.T) {
+	d := DateOf(2021, time.March, 28)
+	if !d.IsDateOnly() || d.String() != "2021-03-28" {
+		t.Errorf("unexpected date: got %s", d)
+	}
+
+	ts := NewDate(time.Date(2021, time.March, 28, 12, 0, 0, 0, time.UTC))
+	if ts.IsDateOnly() || ts.String() != "2021-03-28T12:00:00Z" || ts.Location() != osloLocation || ts.Hour() != 14 {
+		t.Errorf("unexpected timestamp: got %s", ts)
+	}
+}
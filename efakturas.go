@@ -14,20 +14,20 @@ import (
 // Efaktura represents an efaktura.
 // Sbanken API documentation: https://publicapi.sbanken.no/openapi/apibeta/index.html#/Efaktura
 type Efaktura struct {
-	ID                  string  `json:"eFakturaId"`
-	IssuerID            string  `json:"issuerId"`
-	Reference           string  `json:"eFakturaReference"`
-	DocumentType        string  `json:"documentType"`
-	Status              string  `json:"status"`
-	KID                 string  `json:"kid"`
-	OriginalDueDate     string  `json:"originalDueDate"`
-	UpdatedDueDate      string  `json:"updatedDueDate"`
-	NotificationDate    string  `json:"notificationDate"`
-	IssuerName          string  `json:"issuerName"`
-	CreditAccountNumber string  `json:"creditAccountNumber"`
-	OriginalAmount      float32 `json:"originalAmount"`
-	UpdatedAmount       float32 `json:"updatedAmount"`
-	MinimumAmount       float32 `json:"minimumAmount"`
+	ID                  string        `json:"eFakturaId"`
+	IssuerID            string        `json:"issuerId"`
+	Reference           string        `json:"eFakturaReference"`
+	DocumentType        string        `json:"documentType"`
+	Status              string        `json:"status"`
+	KID                 KID           `json:"kid"`
+	OriginalDueDate     Date          `json:"originalDueDate"`
+	UpdatedDueDate      Date          `json:"updatedDueDate"`
+	NotificationDate    Date          `json:"notificationDate"`
+	IssuerName          string        `json:"issuerName"`
+	CreditAccountNumber AccountNumber `json:"creditAccountNumber"`
+	OriginalAmount      Money         `json:"originalAmount"`
+	UpdatedAmount       Money         `json:"updatedAmount"`
+	MinimumAmount       Money         `json:"minimumAmount"`
 }
 
 // EfakturaListQuery represents query parameters for querying efakturas.
@@ -78,13 +78,91 @@ type EfakturaPayQuery struct {
 	PayOnlyMinimumAmount bool   `json:"PayOnlyMinimumAmount"`
 }
 
+// EfakturaPage represents a page of efakturas, together with the total number of available items and the trace ID of the response.
+type EfakturaPage struct {
+	TraceID        string
+	Items          []Efaktura
+	AvailableItems int
+}
+
 // ListEfakturas lists efakturas.
 func (c *Client) ListEfakturas(ctx context.Context, q *EfakturaListQuery) ([]Efaktura, error) {
-	url := fmt.Sprintf("%s/v1/Efakturas", c.bankBaseURL)
+	page, err := c.ListEfakturasPage(ctx, q)
+	if page == nil {
+		return nil, err
+	}
+
+	return page.Items, err
+}
+
+// ListEfakturasPage is like ListEfakturas, but also returns the number of available items and the trace ID.
+func (c *Client) ListEfakturasPage(ctx context.Context, q *EfakturaListQuery) (*EfakturaPage, error) {
+	url := fmt.Sprintf("%s/%s/Efakturas", c.bankBaseURL, c.apiVersion)
 
 	return c.listEfakturas(ctx, url, q, "ListEfakturas")
 }
 
+// IterateEfakturas returns an iterator over all efakturas matching the query.
+// Index and Length of the query are managed by the iterator.
+func (c *Client) IterateEfakturas(q *EfakturaListQuery, opts *PageOptions) *EfakturaIterator {
+	url := fmt.Sprintf("%s/%s/Efakturas", c.bankBaseURL, c.apiVersion)
+
+	return c.newEfakturaIterator(url, q, opts, "ListEfakturas")
+}
+
+// IterateNewEfakturas returns an iterator over all efakturas that have not yet been processed by the customer.
+// Index and Length of the query are managed by the iterator.
+func (c *Client) IterateNewEfakturas(q *EfakturaListQuery, opts *PageOptions) *EfakturaIterator {
+	url := fmt.Sprintf("%s/%s/Efakturas/new", c.bankBaseURL, c.apiVersion)
+
+	it := c.newEfakturaIterator(url, q, opts, "ListNewEfakturas")
+	if err := validateNewEfakturaListQuery(q); err != nil {
+		it.pager.err = err
+	}
+
+	return it
+}
+
+// ListAllEfakturas fetches all efakturas matching the query, one page at a time.
+func (c *Client) ListAllEfakturas(ctx context.Context, q *EfakturaListQuery, opts *PageOptions) ([]Efaktura, error) {
+	return c.IterateEfakturas(q, opts).all(ctx)
+}
+
+// ListAllNewEfakturas fetches all efakturas that have not yet been processed by the customer, one page at a time.
+func (c *Client) ListAllNewEfakturas(ctx context.Context, q *EfakturaListQuery, opts *PageOptions) ([]Efaktura, error) {
+	return c.IterateNewEfakturas(q, opts).all(ctx)
+}
+
+// EfakturaIterator iterates over efakturas across pages.
+type EfakturaIterator struct {
+	*Iterator[Efaktura]
+}
+
+func (c *Client) newEfakturaIterator(url string, q *EfakturaListQuery, opts *PageOptions, caller string) *EfakturaIterator {
+	var query EfakturaListQuery
+	if q != nil {
+		query = *q
+	}
+
+	it := newIterator(query.Index, query.Length, opts, func(ctx context.Context, index, length string) ([]Efaktura, int, error) {
+		query.Index, query.Length = index, length
+
+		page, err := c.listEfakturas(ctx, url, &query, caller)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return page.Items, page.AvailableItems, nil
+	})
+
+	return &EfakturaIterator{it}
+}
+
+// Efaktura returns the current efaktura.
+func (it *EfakturaIterator) Efaktura() Efaktura {
+	return it.Item()
+}
+
 // PayEfaktura pays an efaktura. The EfakturaPayQuery are required.
 func (c *Client) PayEfaktura(ctx context.Context, q *EfakturaPayQuery) error {
 	if q == nil {
@@ -96,51 +174,33 @@ func (c *Client) PayEfaktura(ctx context.Context, q *EfakturaPayQuery) error {
 		return fmt.Errorf("Marshal: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/v1/Efakturas", c.bankBaseURL)
+	url := fmt.Sprintf("%s/%s/Efakturas", c.bankBaseURL, c.apiVersion)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	_, err = do[struct{}](ctx, c, "PayEfaktura", &transport.HTTPRequest{
 		Method:      http.MethodPost,
 		URL:         url,
 		PostPayload: payload,
 	})
-	if err != nil {
-		return fmt.Errorf("request: %w", err)
-	}
 
-	var data transport.HTTPResponse
-	if err := json.Unmarshal(res, &data); err != nil {
-		return fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return &Error{
-			"PayEfaktura",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
-	}
-
-	return nil
+	return err
 }
 
 // ListNewEfakturas lists efakturas that have not yet been processed by the customer.
 func (c *Client) ListNewEfakturas(ctx context.Context, q *EfakturaListQuery) ([]Efaktura, error) {
-	url := fmt.Sprintf("%s/v1/Efakturas/new", c.bankBaseURL)
+	page, err := c.ListNewEfakturasPage(ctx, q)
+	if page == nil {
+		return nil, err
+	}
 
-	if q != nil {
-		if !q.StartDate.IsZero() {
-			return nil, ErrNotValidOptionStartDate
-		}
+	return page.Items, err
+}
 
-		if !q.EndDate.IsZero() {
-			return nil, ErrNotValidOptionEndDate
-		}
+// ListNewEfakturasPage is like ListNewEfakturas, but also returns the number of available items and the trace ID.
+func (c *Client) ListNewEfakturasPage(ctx context.Context, q *EfakturaListQuery) (*EfakturaPage, error) {
+	url := fmt.Sprintf("%s/%s/Efakturas/new", c.bankBaseURL, c.apiVersion)
 
-		if q.Status != "" {
-			return nil, ErrNotValidOptionStatus
-		}
+	if err := validateNewEfakturaListQuery(q); err != nil {
+		return nil, err
 	}
 
 	return c.listEfakturas(ctx, url, q, "ListNewEfakturas")
@@ -152,39 +212,40 @@ func (c *Client) ReadEfaktura(ctx context.Context, efakturaID string) (Efaktura,
 		return Efaktura{}, ErrMissingEfakturaID
 	}
 
-	url := fmt.Sprintf("%s/v1/Efakturas/%s", c.bankBaseURL, efakturaID)
+	url := fmt.Sprintf("%s/%s/Efakturas/%s", c.bankBaseURL, c.apiVersion, efakturaID)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Efaktura](ctx, c, "ReadEfaktura", &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return Efaktura{}, fmt.Errorf("request: %w", err)
+	if data == nil {
+		return Efaktura{}, err
 	}
 
-	data := struct {
-		Efaktura Efaktura `json:"item"`
-		transport.HTTPResponse
-	}{}
+	return data.Item, err
+}
 
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Efaktura, fmt.Errorf("Unmarshal: %w", err)
+func validateNewEfakturaListQuery(q *EfakturaListQuery) error {
+	if q == nil {
+		return nil
 	}
 
-	if data.IsError || sc != http.StatusOK {
-		return data.Efaktura, &Error{
-			"ReadEfaktura",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	if !q.StartDate.IsZero() {
+		return ErrNotValidOptionStartDate
+	}
+
+	if !q.EndDate.IsZero() {
+		return ErrNotValidOptionEndDate
+	}
+
+	if q.Status != "" {
+		return ErrNotValidOptionStatus
 	}
 
-	return data.Efaktura, nil
+	return nil
 }
 
-func (c *Client) listEfakturas(ctx context.Context, url string, q *EfakturaListQuery, caller string) ([]Efaktura, error) {
+func (c *Client) listEfakturas(ctx context.Context, url string, q *EfakturaListQuery, caller string) (*EfakturaPage, error) {
 	if q != nil {
 		qs, err := q.QueryString(url)
 		if err != nil {
@@ -194,32 +255,17 @@ func (c *Client) listEfakturas(ctx context.Context, url string, q *EfakturaListQ
 		url = fmt.Sprintf("%s?%s", url, qs)
 	}
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Efaktura](ctx, c, caller, &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
-	}
-
-	data := struct {
-		Efakturas []Efaktura `json:"items"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Efakturas, fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return data.Efakturas, &Error{
-			caller,
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	if data == nil {
+		return nil, err
 	}
 
-	return data.Efakturas, nil
+	return &EfakturaPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
@@ -20,11 +20,11 @@ var testEfaktura = Efaktura{
 	DocumentType:        "doctype",
 	Status:              "NEW",
 	KID:                 "000098765432123456789",
-	OriginalDueDate:     time.Now().String(),
-	NotificationDate:    time.Now().String(),
+	OriginalDueDate:     mustParseDate("2021-02-15T00:00:00"),
+	NotificationDate:    mustParseDate("2021-02-01T08:30:00+01:00"),
 	IssuerName:          "Hello",
-	OriginalAmount:      133.33,
-	MinimumAmount:       100.00,
+	OriginalAmount:      13333,
+	MinimumAmount:       10000,
 	CreditAccountNumber: "998877665544332211",
 }
 
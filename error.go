@@ -3,6 +3,8 @@ package sbanken
 import (
 	"errors"
 	"fmt"
+
+	"github.com/engvik/sbanken-go/internal/transport"
 )
 
 var (
@@ -22,6 +24,22 @@ var (
 	ErrMissingClientID = errors.New("ClientID must be set")
 	// ErrMissingClientSecret are returned when ClientSecret is not set.
 	ErrMissingClientSecret = errors.New("ClientSecret must be set")
+	// ErrInvalidBaseURL are returned when BaseURL is not a valid http(s) URL.
+	ErrInvalidBaseURL = errors.New("BaseURL must be a valid http(s) URL")
+	// ErrInvalidAuthURL are returned when AuthURL is not a valid http(s) URL.
+	ErrInvalidAuthURL = errors.New("AuthURL must be a valid http(s) URL")
+	// ErrInvalidAPIVersion are returned when APIVersion is not a single path segment.
+	ErrInvalidAPIVersion = errors.New("APIVersion must be a single path segment")
+	// ErrInvalidTokenRefreshSkew are returned when TokenRefreshSkew is negative.
+	ErrInvalidTokenRefreshSkew = errors.New("TokenRefreshSkew must not be negative")
+	// ErrInvalidRetryJitter are returned when RetryPolicy.Jitter is not between 0 and 1.
+	ErrInvalidRetryJitter = errors.New("RetryPolicy.Jitter must be between 0 and 1")
+	// ErrInvalidRateLimit are returned when RateLimit or RateBurst is negative.
+	ErrInvalidRateLimit = errors.New("RateLimit and RateBurst must not be negative")
+	// ErrInvalidMaxBodySize are returned when MaxBodySize is negative.
+	ErrInvalidMaxBodySize = errors.New("MaxBodySize must not be negative")
+	// ErrInvalidTimeout are returned when the timeout passed to WithTimeout is negative.
+	ErrInvalidTimeout = errors.New("timeout must not be negative")
 	// ErrNotValidOptionStartDate are returned when StartDate is not allowed.
 	ErrNotValidOptionStartDate = errors.New("StartDate is not valid option for this method")
 	// ErrNotValidOptionEndDate are returned when EndDate is not allowed.
@@ -37,11 +55,69 @@ type Error struct {
 	Message     string
 	Code        int
 	StatusCode  int
+	// TraceID is the trace ID returned by the API, useful when contacting Sbanken support.
+	TraceID string
+	// Err is the underlying cause when the request failed before a response was received,
+	// such as a *NetworkError or an *AuthError.
+	Err error
+}
+
+func newResponseError(caller string, res transport.HTTPResponse, statusCode int) *Error {
+	return &Error{
+		ErrorString: caller,
+		Type:        res.ErrorType,
+		Message:     res.ErrorMessage,
+		Code:        res.ErrorCode,
+		StatusCode:  statusCode,
+		TraceID:     res.TraceID,
+	}
+}
+
+func newRequestError(caller string, err error) *Error {
+	e := &Error{
+		ErrorString: caller,
+		Err:         err,
+	}
+
+	// Unexpected responses, such as HTML error pages, still have a status code worth matching on.
+	var unexpectedErr *UnexpectedResponseError
+	if errors.As(err, &unexpectedErr) {
+		e.StatusCode = unexpectedErr.StatusCode
+	}
+
+	return e
+}
+
+// newDecodeError returns the error of a response body that could not be decoded. It keeps the status
+// code, so that e.g. a 503 response with a broken body still matches ErrServiceUnavailable.
+func newDecodeError(caller string, err error, statusCode int) *Error {
+	return &Error{
+		ErrorString: caller,
+		StatusCode:  statusCode,
+		Err:         &decodeError{err: err},
+	}
+}
+
+// decodeError marks the cause of an *Error as a response body that could not be decoded.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
 }
 
 // Error returns the string representation of the error.
 func (e *Error) Error() string {
-	return fmt.Sprintf(
+	if e.Err != nil {
+		return fmt.Sprintf("%s error: request: %s", e.ErrorString, e.Err)
+	}
+
+	str := fmt.Sprintf(
 		"%s error: %s (StatusCode: %d / ErrorCode: %d): %s",
 		e.ErrorString,
 		e.Type,
@@ -49,4 +125,63 @@ func (e *Error) Error() string {
 		e.Code,
 		e.Message,
 	)
+
+	if e.TraceID != "" {
+		str = fmt.Sprintf("%s (TraceID: %s)", str, e.TraceID)
+	}
+
+	return str
 }
+
+// Unwrap returns the underlying cause of the error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NetworkError represents a failure to get a response from the API. It wraps the underlying
+// error, and matches one of ErrTimeout, ErrDNS, ErrConnectionRefused, ErrTLS, ErrCanceled and
+// ErrNetwork with errors.Is:
+//
+//	if errors.Is(err, sbanken.ErrTimeout) {
+//		...
+//	}
+type NetworkError = transport.NetworkError
+
+var (
+	// ErrTimeout are returned when a request times out.
+	ErrTimeout = transport.ErrTimeout
+	// ErrDNS are returned when the host name of the API can not be resolved.
+	ErrDNS = transport.ErrDNS
+	// ErrConnectionRefused are returned when the API refuses the connection.
+	ErrConnectionRefused = transport.ErrConnectionRefused
+	// ErrTLS are returned when the TLS handshake or certificate verification fails.
+	ErrTLS = transport.ErrTLS
+	// ErrCanceled are returned when the request is cancelled by the context.
+	ErrCanceled = transport.ErrCanceled
+	// ErrNetwork are returned for other network failures.
+	ErrNetwork = transport.ErrNetwork
+	// ErrMissingToken are returned when a TokenSource returns an empty token.
+	ErrMissingToken = transport.ErrMissingToken
+)
+
+// AuthError represents an error response from the identity server. It is returned
+// from NewClient, and from any call that refreshes the access token, and can be
+// reached with errors.As:
+//
+//	var authErr *sbanken.AuthError
+//	if errors.As(err, &authErr) && authErr.InvalidCredentials() {
+//		...
+//	}
+type AuthError = transport.AuthError
+
+// UnexpectedResponseError represents a response that is not a JSON API response, such as an HTML
+// error page from a proxy, or a body exceeding Config.MaxBodySize. It includes the start of the body,
+// and matches one of ErrUnexpectedContentType and ErrResponseTooLarge with errors.Is.
+type UnexpectedResponseError = transport.UnexpectedResponseError
+
+var (
+	// ErrUnexpectedContentType are returned when the response is not JSON.
+	ErrUnexpectedContentType = transport.ErrUnexpectedContentType
+	// ErrResponseTooLarge are returned when the response body exceeds Config.MaxBodySize.
+	ErrResponseTooLarge = transport.ErrResponseTooLarge
+)
@@ -0,0 +1,133 @@
+package sbanken
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for known API failures. An *Error returned from the API
+// matches them with errors.Is, based on the errorType of the response, and on
+// its HTTP status code and operation as a fallback:
+//
+//	if errors.Is(err, sbanken.ErrInsufficientFunds) {
+//		...
+//	}
+//
+// Errors that are not in the catalogue only match the generic *Error.
+var (
+	// ErrInsufficientFunds are matched when the account does not have enough funds for a transfer or payment.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrAccountNotFound are matched when the account does not exist or is not accessible.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrNotFound are matched when the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidInput are matched when the API rejects the request parameters.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrUnauthorized are matched when the access token is missing, invalid or expired.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden are matched when the client is not allowed to perform the operation.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited are matched when the API throttles the client.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServiceUnavailable are matched when the API is temporarily unavailable.
+	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrInternal are matched when the API fails with an internal error.
+	ErrInternal = errors.New("internal server error")
+)
+
+// errorMapping maps an API error to a sentinel. Empty fields match any value.
+type errorMapping struct {
+	err        error
+	errorType  string
+	operations []string
+	statusCode int
+}
+
+// accountOperations are the operations referring to accounts by ID, where 404 means the account does not exist.
+var accountOperations = []string{
+	"ReadAccount",
+	"ListTransactions",
+	"ListArchivedTransactions",
+	"ListPayments",
+	"ReadPayment",
+	"ListStandingOrders",
+	"Transfer",
+}
+
+// errorCatalogue lists the known API errors. An error may match several entries,
+// e.g. an unknown account matches both ErrAccountNotFound and ErrNotFound.
+//
+// The error types are the ones returned by the API for these failures, as emulated
+// by sbankentest. The API does not document numeric error codes, so none are mapped;
+// they are still available as Code on *Error.
+var errorCatalogue = []errorMapping{
+	{err: ErrInsufficientFunds, errorType: "InsufficientFunds"},
+	{err: ErrAccountNotFound, errorType: "AccountNotFound"},
+	{err: ErrNotFound, errorType: "AccountNotFound"},
+	{err: ErrNotFound, errorType: "NotFound"},
+	{err: ErrInvalidInput, errorType: "Input"},
+	{err: ErrUnauthorized, errorType: "Unauthorized"},
+	{err: ErrServiceUnavailable, errorType: "ServiceUnavailable"},
+	{err: ErrInternal, errorType: "System"},
+
+	// Status codes are the fallback for error types not listed above.
+	{err: ErrAccountNotFound, statusCode: http.StatusNotFound, operations: accountOperations},
+	{err: ErrNotFound, statusCode: http.StatusNotFound},
+	{err: ErrInvalidInput, statusCode: http.StatusBadRequest},
+	{err: ErrUnauthorized, statusCode: http.StatusUnauthorized},
+	{err: ErrForbidden, statusCode: http.StatusForbidden},
+	{err: ErrRateLimited, statusCode: http.StatusTooManyRequests},
+	{err: ErrServiceUnavailable, statusCode: http.StatusServiceUnavailable},
+	{err: ErrServiceUnavailable, statusCode: http.StatusGatewayTimeout},
+	{err: ErrInternal, statusCode: http.StatusInternalServerError},
+}
+
+func (m *errorMapping) matches(e *Error) bool {
+	if m.errorType != "" && !strings.EqualFold(m.errorType, e.Type) {
+		return false
+	}
+
+	if m.statusCode != 0 && m.statusCode != e.StatusCode {
+		return false
+	}
+
+	if len(m.operations) > 0 {
+		for _, op := range m.operations {
+			if op == e.ErrorString {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// Is reports whether the error matches target in the catalogue of known API errors.
+// Requests failing without a response never match, while unexpected responses, such as
+// HTML error pages, and responses that could not be decoded match on their status code.
+func (e *Error) Is(target error) bool {
+	if e.Err != nil && !hasResponse(e.Err) {
+		return false
+	}
+
+	for i := range errorCatalogue {
+		if errorCatalogue[i].err == target && errorCatalogue[i].matches(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasResponse reports whether err is the cause of an *Error for a response that was received.
+func hasResponse(err error) bool {
+	var (
+		unexpectedErr *UnexpectedResponseError
+		decodeErr     *decodeError
+	)
+
+	return errors.As(err, &unexpectedErr) || errors.As(err, &decodeErr)
+}
@@ -0,0 +1,134 @@
+package sbanken
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		exp    []error
+		expNot []error
+	}{
+		{
+			name:   "should match insufficient funds by type",
+			err:    &Error{ErrorString: "Transfer", Type: "InsufficientFunds", StatusCode: 400},
+			exp:    []error{ErrInsufficientFunds, ErrInvalidInput},
+			expNot: []error{ErrNotFound},
+		},
+		{
+			name: "should match error type case insensitively",
+			err:  &Error{ErrorString: "Transfer", Type: "insufficientfunds", StatusCode: 400},
+			exp:  []error{ErrInsufficientFunds},
+		},
+		{
+			name:   "should match error type of error envelope",
+			err:    &Error{ErrorString: "ListAccounts", Type: "System", StatusCode: 200},
+			exp:    []error{ErrInternal},
+			expNot: []error{ErrServiceUnavailable},
+		},
+		{
+			name: "should match unknown account by type",
+			err:  &Error{ErrorString: "ListEfakturas", Type: "AccountNotFound", StatusCode: 404},
+			exp:  []error{ErrAccountNotFound, ErrNotFound},
+		},
+		{
+			name:   "should match invalid input by status code",
+			err:    &Error{ErrorString: "Transfer", Type: "Input", StatusCode: 400},
+			exp:    []error{ErrInvalidInput},
+			expNot: []error{ErrNotFound},
+		},
+		{
+			name:   "should match unknown account on account operations",
+			err:    &Error{ErrorString: "ReadAccount", StatusCode: 404},
+			exp:    []error{ErrAccountNotFound, ErrNotFound},
+			expNot: []error{ErrInvalidInput},
+		},
+		{
+			name:   "should not match unknown account on other operations",
+			err:    &Error{ErrorString: "ReadEfaktura", StatusCode: 404},
+			exp:    []error{ErrNotFound},
+			expNot: []error{ErrAccountNotFound},
+		},
+		{
+			name: "should match error type and status code",
+			err:  &Error{ErrorString: "ListAccounts", Type: "ServiceUnavailable", StatusCode: 500},
+			exp:  []error{ErrServiceUnavailable, ErrInternal},
+		},
+		{
+			name:   "should fall back to status code for unknown types",
+			err:    &Error{ErrorString: "ListAccounts", Type: "Unknown", StatusCode: 503},
+			exp:    []error{ErrServiceUnavailable},
+			expNot: []error{ErrInsufficientFunds, ErrInternal},
+		},
+		{
+			name:   "should match unauthorized",
+			err:    &Error{ErrorString: "ListAccounts", StatusCode: 401},
+			exp:    []error{ErrUnauthorized},
+			expNot: []error{ErrForbidden},
+		},
+		{
+			name: "should match rate limited",
+			err:  &Error{ErrorString: "ListAccounts", StatusCode: 429},
+			exp:  []error{ErrRateLimited},
+		},
+		{
+			name: "should match wrapped errors",
+			err:  fmt.Errorf("wrapped: %w", &Error{ErrorString: "ListCards", StatusCode: 403}),
+			exp:  []error{ErrForbidden},
+		},
+		{
+			name:   "should fall back to generic error for unknown codes",
+			err:    &Error{ErrorString: "ListAccounts", Type: "Unknown", Code: 42, StatusCode: 418},
+			expNot: []error{ErrInsufficientFunds, ErrAccountNotFound, ErrNotFound, ErrInvalidInput, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrServiceUnavailable, ErrInternal},
+		},
+		{
+			name:   "should not match request errors",
+			err:    newRequestError("ListAccounts", ErrTimeout),
+			exp:    []error{ErrTimeout},
+			expNot: []error{ErrServiceUnavailable, ErrInternal},
+		},
+		{
+			name:   "should not match request errors with status code",
+			err:    &Error{ErrorString: "ListAccounts", StatusCode: 503, Err: ErrTimeout},
+			exp:    []error{ErrTimeout},
+			expNot: []error{ErrServiceUnavailable},
+		},
+		{
+			name:   "should match undecodable responses by status code",
+			err:    newDecodeError("ListAccounts", errors.New("unexpected end of JSON input"), 500),
+			exp:    []error{ErrInternal},
+			expNot: []error{ErrServiceUnavailable},
+		},
+		{
+			name:   "should match unexpected responses by status code",
+			err:    newRequestError("ListAccounts", &UnexpectedResponseError{Kind: ErrUnexpectedContentType, StatusCode: 503}),
+			exp:    []error{ErrUnexpectedContentType, ErrServiceUnavailable},
+			expNot: []error{ErrInternal},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, target := range tc.exp {
+				if !errors.Is(tc.err, target) {
+					t.Errorf("expected %v to match %v", tc.err, target)
+				}
+			}
+
+			for _, target := range tc.expNot {
+				if errors.Is(tc.err, target) {
+					t.Errorf("expected %v not to match %v", tc.err, target)
+				}
+			}
+
+			var sErr *Error
+			if !errors.As(tc.err, &sErr) {
+				t.Errorf("expected %v to be an *Error", tc.err)
+			}
+		})
+	}
+}
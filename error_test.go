@@ -1,6 +1,13 @@
 package sbanken
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func getTestError(str string) *Error {
 	return &Error{
@@ -28,6 +35,18 @@ func TestError(t *testing.T) {
 			},
 			"TestError error: Test (StatusCode: 500 / ErrorCode: 0): an error occurred",
 		},
+		{
+			"should include trace ID",
+			&Error{
+				ErrorString: "TestError",
+				Type:        "Test",
+				StatusCode:  500,
+				Code:        100,
+				Message:     "an error occurred",
+				TraceID:     "0HM5B1ERQ6TF4:00000001",
+			},
+			"TestError error: Test (StatusCode: 500 / ErrorCode: 100): an error occurred (TraceID: 0HM5B1ERQ6TF4:00000001)",
+		},
 	}
 
 	for _, tc := range tests {
@@ -38,3 +57,86 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorWrapsCause(t *testing.T) {
+	ctx := context.Background()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	addr := "http://" + l.Addr().String()
+	l.Close()
+
+	c, err := NewClient(ctx, &Config{
+		ClientID:     "some-client-id",
+		ClientSecret: "some-client-secret",
+		BaseURL:      addr,
+		AuthURL:      addr + "/connect/token",
+		LazyAuth:     true,
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	_, err = c.ListAccounts(ctx)
+
+	var sErr *Error
+	if !errors.As(err, &sErr) || sErr.ErrorString != "ListAccounts" {
+		t.Fatalf("unexpected error: got %v", err)
+	}
+
+	if !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("expected error to wrap ErrConnectionRefused: got %v", err)
+	}
+
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("expected error to wrap NetworkError: got %v", err)
+	}
+}
+
+func TestErrorUnexpectedResponse(t *testing.T) {
+	ctx := context.Background()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v1/Accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html><body>Service Unavailable</body></html>"))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(ctx, &Config{
+		ClientID:     "some-client-id",
+		ClientSecret: "some-client-secret",
+		BaseURL:      srv.URL + "/api",
+		AuthURL:      srv.URL + "/connect/token",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	_, err = c.ListAccounts(ctx)
+
+	var unexpectedErr *UnexpectedResponseError
+	if !errors.As(err, &unexpectedErr) {
+		t.Fatalf("expected UnexpectedResponseError: got %v", err)
+	}
+
+	if unexpectedErr.Snippet != "<html><body>Service Unavailable</body></html>" {
+		t.Errorf("unexpected snippet: got %q", unexpectedErr.Snippet)
+	}
+
+	for _, target := range []error{ErrUnexpectedContentType, ErrServiceUnavailable} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected error to match %v: got %v", target, err)
+		}
+	}
+}
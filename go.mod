@@ -1,3 +1,3 @@
 module github.com/engvik/sbanken-go
 
-go 1.15
+go 1.18
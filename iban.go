@@ -0,0 +1,207 @@
+package sbanken
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidIBAN are returned when an IBAN does not have the format of an IBAN, or of a Norwegian IBAN where required.
+	ErrInvalidIBAN = errors.New("invalid IBAN")
+	// ErrIBANChecksum are returned when the ISO 13616 check digits of an IBAN do not match.
+	ErrIBANChecksum = errors.New("invalid IBAN check digits")
+	// ErrUnknownBIC are returned when the BIC of a bank registration number is not known.
+	ErrUnknownBIC = errors.New("unknown BIC")
+)
+
+const (
+	// norwayCountryCode is the country code of Norwegian IBANs.
+	norwayCountryCode = "NO"
+	// norwayIBANLength is the length of Norwegian IBANs, the country code and check digits followed by the 11 digit account number.
+	norwayIBANLength = 15
+
+	minIBANLength = 15
+	maxIBANLength = 34
+)
+
+// IBAN represents an International Bank Account Number, such as "NO93 8601 1117 947".
+// Parsed IBANs hold the characters without spaces, in upper case.
+type IBAN string
+
+// ParseIBAN parses an IBAN, with or without spaces, and validates its ISO 13616 check digits.
+// IBANs of all countries are accepted, but Norwegian IBANs must also have 15 characters.
+func ParseIBAN(s string) (IBAN, error) {
+	i := IBAN(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", "")))
+
+	if len(i) < minIBANLength || len(i) > maxIBANLength || !isLetters(string(i[:2])) || !isDigits(string(i[2:4])) || !isAlphanumeric(string(i[4:])) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIBAN, s)
+	}
+
+	if i.CountryCode() == norwayCountryCode && len(i) != norwayIBANLength {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIBAN, s)
+	}
+
+	if ibanMod97(string(i[4:])+string(i[:4])) != 1 {
+		return "", fmt.Errorf("%w: %q", ErrIBANChecksum, s)
+	}
+
+	return i, nil
+}
+
+// IBAN returns the Norwegian IBAN of the account number.
+func (a AccountNumber) IBAN() (IBAN, error) {
+	digits, err := ParseAccountNumber(string(a))
+	if err != nil {
+		return "", err
+	}
+
+	check := 98 - ibanMod97(string(digits)+norwayCountryCode+"00")
+
+	return IBAN(fmt.Sprintf("%s%02d%s", norwayCountryCode, check, string(digits))), nil
+}
+
+// BIC returns the BIC of the bank of the account number, see BICForRegistrationNumber.
+func (a AccountNumber) BIC() (string, error) {
+	digits, err := ParseAccountNumber(string(a))
+	if err != nil {
+		return "", err
+	}
+
+	return BICForRegistrationNumber(string(digits[:4]))
+}
+
+// Valid reports whether the IBAN has valid check digits.
+func (i IBAN) Valid() bool {
+	_, err := ParseIBAN(string(i))
+	return err == nil
+}
+
+// CountryCode returns the country code of the IBAN, such as "NO".
+func (i IBAN) CountryCode() string {
+	if len(i) < 2 {
+		return ""
+	}
+
+	return strings.ToUpper(string(i[:2]))
+}
+
+// AccountNumber returns the Norwegian account number (BBAN) of a Norwegian IBAN.
+func (i IBAN) AccountNumber() (AccountNumber, error) {
+	parsed, err := ParseIBAN(string(i))
+	if err != nil {
+		return "", err
+	}
+
+	if parsed.CountryCode() != norwayCountryCode {
+		return "", fmt.Errorf("%w: not a Norwegian IBAN: %q", ErrInvalidIBAN, string(i))
+	}
+
+	return ParseAccountNumber(string(parsed[4:]))
+}
+
+// String returns the IBAN in the print format, in groups of four characters. IBANs that are
+// not valid are returned as they are.
+func (i IBAN) String() string {
+	parsed, err := ParseIBAN(string(i))
+	if err != nil {
+		return string(i)
+	}
+
+	var groups []string
+	for s := string(parsed); s != ""; {
+		n := 4
+		if len(s) < n {
+			n = len(s)
+		}
+
+		groups = append(groups, s[:n])
+		s = s[n:]
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// IBAN returns the IBAN of the account.
+func (a Account) IBAN() (IBAN, error) {
+	return a.Number.IBAN()
+}
+
+// BIC returns the BIC of the bank of the account.
+func (a Account) BIC() (string, error) {
+	return a.Number.BIC()
+}
+
+// bicRange assigns a BIC to the registration numbers from first to last, inclusive.
+type bicRange struct {
+	first string
+	last  string
+	bic   string
+}
+
+// bics maps ranges of bank registration numbers to BICs, sorted by first registration number.
+// The ranges are taken from the list of registration numbers, IBAN and BIC of Norwegian banks
+// maintained by Bits AS, the infrastructure company of the Norwegian banks. Only ranges known to
+// belong to a single bank are listed, as a wrong BIC may misdirect a payment.
+var bics = []bicRange{
+	{first: "1200", last: "1299", bic: "DNBANOKK"}, // DNB
+	{first: "1500", last: "1699", bic: "DNBANOKK"}, // DNB
+	{first: "3200", last: "3299", bic: "SPRONO22"}, // SpareBank 1 SR-Bank
+	{first: "4200", last: "4299", bic: "SPTRNO22"}, // SpareBank 1 SMN
+	{first: "4500", last: "4599", bic: "SNOWNO22"}, // SpareBank 1 Nord-Norge
+	{first: "6000", last: "6199", bic: "NDEANOKK"}, // Nordea
+	{first: "9710", last: "9710", bic: "SBAKNOBB"}, // Sbanken
+}
+
+// BICForRegistrationNumber returns the BIC of the bank with the registration number, the first four
+// digits of an account number. It returns ErrUnknownBIC if the registration number is not known.
+func BICForRegistrationNumber(registrationNumber string) (string, error) {
+	if len(registrationNumber) == 4 {
+		i := sort.Search(len(bics), func(i int) bool { return bics[i].last >= registrationNumber })
+		if i < len(bics) && bics[i].first <= registrationNumber {
+			return bics[i].bic, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: registration number %q", ErrUnknownBIC, registrationNumber)
+}
+
+// ibanMod97 returns the remainder of the number given by s divided by 97, where letters
+// count as two digits, A as 10 to Z as 35.
+func ibanMod97(s string) int {
+	rem := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case isDigit(c):
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A'+10)) % 97
+		}
+	}
+
+	return rem
+}
+
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,165 @@
+package sbanken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIBAN(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		exp    IBAN
+		expErr error
+	}{
+		{name: "should parse Norwegian IBAN", in: "NO9386011117947", exp: "NO9386011117947"},
+		{name: "should parse print format", in: "NO93 8601 1117 947", exp: "NO9386011117947"},
+		{name: "should parse lower case", in: "no9386011117947", exp: "NO9386011117947"},
+		{name: "should parse foreign IBAN", in: "DE89 3704 0044 0532 0130 00", exp: "DE89370400440532013000"},
+		{name: "should parse IBAN with letters", in: "GB82WEST12345698765432", exp: "GB82WEST12345698765432"},
+		{name: "should fail on wrong check digits", in: "NO9486011117947", expErr: ErrIBANChecksum},
+		{name: "should fail on swapped digits", in: "NO9386011117974", expErr: ErrIBANChecksum},
+		{name: "should fail on wrong Norwegian length", in: "NO93860111179470", expErr: ErrInvalidIBAN},
+		{name: "should fail on missing country code", in: "9386011117947", expErr: ErrInvalidIBAN},
+		{name: "should fail on invalid characters", in: "NO93-8601-1117-947", expErr: ErrInvalidIBAN},
+		{name: "should fail on empty string", in: "", expErr: ErrInvalidIBAN},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			i, err := ParseIBAN(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if i != tc.exp {
+				t.Errorf("unexpected IBAN: got %q, exp %q", i, tc.exp)
+			}
+		})
+	}
+}
+
+func TestAccountNumberIBAN(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      AccountNumber
+		exp    IBAN
+		expErr error
+	}{
+		{name: "should convert account number", a: "86011117947", exp: "NO9386011117947"},
+		{name: "should convert Sbanken account number", a: "97104133219", exp: "NO9297104133219"},
+		{name: "should convert formatted account number", a: "1234.56.78903", exp: "NO7112345678903"},
+		{name: "should fail on invalid account number", a: "97104133218", expErr: ErrAccountNumberCheckDigit},
+		{name: "should fail on empty account number", expErr: ErrInvalidAccountNumber},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			i, err := tc.a.IBAN()
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if i != tc.exp {
+				t.Errorf("unexpected IBAN: got %q, exp %q", i, tc.exp)
+			}
+
+			if tc.expErr != nil {
+				return
+			}
+
+			if !i.Valid() {
+				t.Errorf("IBAN not valid: %q", i)
+			}
+
+			a, err := i.AccountNumber()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if a.String() != tc.a.String() {
+				t.Errorf("unexpected account number: got %q, exp %q", a, tc.a)
+			}
+		})
+	}
+}
+
+func TestIBANAccountNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		i      IBAN
+		exp    AccountNumber
+		expErr error
+	}{
+		{name: "should convert Norwegian IBAN", i: "NO93 8601 1117 947", exp: "86011117947"},
+		{name: "should fail on foreign IBAN", i: "DE89370400440532013000", expErr: ErrInvalidIBAN},
+		{name: "should fail on invalid IBAN", i: "NO9486011117947", expErr: ErrIBANChecksum},
+		{name: "should fail on invalid account number check digit", i: "NO6686011117948", expErr: ErrAccountNumberCheckDigit},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := tc.i.AccountNumber()
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if a != tc.exp {
+				t.Errorf("unexpected account number: got %q, exp %q", a, tc.exp)
+			}
+		})
+	}
+}
+
+func TestIBANString(t *testing.T) {
+	tests := []struct {
+		name string
+		i    IBAN
+		exp  string
+	}{
+		{name: "should format Norwegian IBAN", i: "NO9386011117947", exp: "NO93 8601 1117 947"},
+		{name: "should format foreign IBAN", i: "DE89370400440532013000", exp: "DE89 3704 0044 0532 0130 00"},
+		{name: "should keep invalid IBAN", i: "NO9486011117947", exp: "NO9486011117947"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.i.String(); got != tc.exp {
+				t.Errorf("unexpected string: got %q, exp %q", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestBIC(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      Account
+		exp    string
+		expErr error
+	}{
+		{name: "should look up Sbanken", a: Account{Number: "97104133219"}, exp: "SBAKNOBB"},
+		{name: "should look up DNB", a: Account{Number: "15031234562"}, exp: "DNBANOKK"},
+		{name: "should look up end of range", a: Account{Number: "12990000008"}, exp: "DNBANOKK"},
+		{name: "should look up SpareBank 1 SR-Bank", a: Account{Number: "32011234568"}, exp: "SPRONO22"},
+		{name: "should look up SpareBank 1 SMN", a: Account{Number: "42001234565"}, exp: "SPTRNO22"},
+		{name: "should look up SpareBank 1 Nord-Norge", a: Account{Number: "45001234564"}, exp: "SNOWNO22"},
+		{name: "should look up Nordea", a: Account{Number: "60051234564"}, exp: "NDEANOKK"},
+		{name: "should fail on unknown bank", a: Account{Number: "86011117947"}, expErr: ErrUnknownBIC},
+		{name: "should fail next to a range", a: Account{Number: "97111234578"}, expErr: ErrUnknownBIC},
+		{name: "should fail on invalid account number", a: Account{Number: "97104133218"}, expErr: ErrAccountNumberCheckDigit},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bic, err := tc.a.BIC()
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if bic != tc.exp {
+				t.Errorf("unexpected BIC: got %q, exp %q", bic, tc.exp)
+			}
+		})
+	}
+}
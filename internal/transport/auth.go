@@ -1,14 +1,9 @@
 package transport
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"net/url"
-	"time"
 )
 
 type auth struct {
@@ -16,62 +11,59 @@ type auth struct {
 	TokenType   string `json:"token_type"`
 	Scope       string `json:"scope"`
 	ExpiresIn   int    `json:"expires_in"`
-	expires     time.Time
 }
 
-// Authorize fetches a token for accessing the APIs.
-func (c *Client) Authorize(ctx context.Context) error {
-	authURL := "https://auth.sbanken.no/identityserver/connect/token"
-	payload := []byte("grant_type=client_credentials")
-
-	req, err := http.NewRequest(http.MethodPost, authURL, bytes.NewBuffer(payload))
-	if err != nil {
-		return err
-	}
-
-	req = req.WithContext(ctx)
-
-	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
-	req.Header.Set("Accept", "application/json")
-
-	res, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-
-	defer res.Body.Close()
+// AuthError represents an error response from the identity server.
+// See RFC 6749 section 5.2 for the standard error codes.
+type AuthError struct {
+	// Code is the OAuth error code, such as "invalid_client". It is empty if the response body was not an OAuth error.
+	Code string `json:"error"`
+	// Description is the human readable error description, if any.
+	Description string `json:"error_description"`
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+}
 
-	if res.StatusCode != http.StatusOK {
-		// TODO: Handle error from body
-		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+// Error returns the string representation of the error.
+func (e *AuthError) Error() string {
+	switch {
+	case e.Code != "" && e.Description != "":
+		return fmt.Sprintf("authorization failed (StatusCode: %d): %s: %s", e.StatusCode, e.Code, e.Description)
+	case e.Code != "":
+		return fmt.Sprintf("authorization failed (StatusCode: %d): %s", e.StatusCode, e.Code)
+	default:
+		return fmt.Sprintf("authorization failed (StatusCode: %d)", e.StatusCode)
 	}
+}
 
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
+// InvalidCredentials reports whether the identity server rejected the client credentials,
+// for instance because the client ID is unknown or the client secret is wrong or expired.
+func (e *AuthError) InvalidCredentials() bool {
+	switch e.Code {
+	case "invalid_client", "unauthorized_client", "invalid_grant":
+		return true
 	}
 
-	var a auth
-	if err := json.Unmarshal(data, &a); err != nil {
-		return err
-	}
+	return e.Code == "" && e.StatusCode == http.StatusUnauthorized
+}
 
-	exp := time.Now().Add(time.Second * time.Duration(a.ExpiresIn))
-	a.expires = exp
+// Temporary reports whether the error is likely to be temporary, such as a server outage or throttling.
+func (e *AuthError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
 
-	c.auth = &a
+// Authorize fetches a token for accessing the APIs from the token source. Concurrent calls share a single request to the source.
+func (c *Client) Authorize(ctx context.Context) error {
+	_, err := c.tokens.refresh(ctx)
 
-	return nil
+	return err
 }
 
 func (c *Client) getToken(ctx context.Context) (string, error) {
-	if time.Now().After(c.auth.expires) {
-		err := c.Authorize(ctx)
-		if err != nil {
-			return "", fmt.Errorf("error renewing token: %w", err)
-		}
+	token, err := c.tokens.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error renewing token: %w", err)
 	}
 
-	return c.auth.AccessToken, nil
+	return token, nil
 }
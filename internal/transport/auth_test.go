@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorizeError(t *testing.T) {
+	tests := []struct {
+		name                  string
+		status                int
+		body                  string
+		expCode               string
+		expDescription        string
+		expInvalidCredentials bool
+		expTemporary          bool
+		expErrorString        string
+	}{
+		{
+			name:                  "should decode invalid client",
+			status:                http.StatusBadRequest,
+			body:                  `{"error":"invalid_client"}`,
+			expCode:               "invalid_client",
+			expInvalidCredentials: true,
+			expErrorString:        "authorization failed (StatusCode: 400): invalid_client",
+		},
+		{
+			name:                  "should decode description",
+			status:                http.StatusBadRequest,
+			body:                  `{"error":"unauthorized_client","error_description":"client secret expired"}`,
+			expCode:               "unauthorized_client",
+			expDescription:        "client secret expired",
+			expInvalidCredentials: true,
+			expErrorString:        "authorization failed (StatusCode: 400): unauthorized_client: client secret expired",
+		},
+		{
+			name:                  "should handle unauthorized without body",
+			status:                http.StatusUnauthorized,
+			expInvalidCredentials: true,
+			expErrorString:        "authorization failed (StatusCode: 401)",
+		},
+		{
+			name:           "should handle server outage with html body",
+			status:         http.StatusServiceUnavailable,
+			body:           `<html><body>Service Unavailable</body></html>`,
+			expTemporary:   true,
+			expErrorString: "authorization failed (StatusCode: 503)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := newTestClient(srv, 0)
+
+			err := c.Authorize(context.Background())
+
+			var authErr *AuthError
+			if !errors.As(err, &authErr) {
+				t.Fatalf("unexpected error: got %v", err)
+			}
+
+			if authErr.Code != tc.expCode || authErr.Description != tc.expDescription || authErr.StatusCode != tc.status {
+				t.Errorf("unexpected auth error: got %+v", authErr)
+			}
+
+			if authErr.InvalidCredentials() != tc.expInvalidCredentials {
+				t.Errorf("unexpected invalid credentials: got %t", authErr.InvalidCredentials())
+			}
+
+			if authErr.Temporary() != tc.expTemporary {
+				t.Errorf("unexpected temporary: got %t", authErr.Temporary())
+			}
+
+			if err.Error() != tc.expErrorString {
+				t.Errorf("unexpected error string: got %s, exp %s", err.Error(), tc.expErrorString)
+			}
+
+			if DefaultShouldRetry(0, err) != tc.expTemporary {
+				t.Errorf("unexpected retry decision for %v", err)
+			}
+		})
+	}
+}
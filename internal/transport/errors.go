@@ -0,0 +1,120 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+var (
+	// ErrTimeout are returned when a request times out.
+	ErrTimeout = errors.New("timeout")
+	// ErrDNS are returned when the host name of the API can not be resolved.
+	ErrDNS = errors.New("dns lookup failed")
+	// ErrConnectionRefused are returned when the API refuses the connection.
+	ErrConnectionRefused = errors.New("connection refused")
+	// ErrTLS are returned when the TLS handshake or certificate verification fails.
+	ErrTLS = errors.New("tls failure")
+	// ErrCanceled are returned when the request is cancelled by the context.
+	ErrCanceled = errors.New("request canceled")
+	// ErrNetwork are returned for other network failures.
+	ErrNetwork = errors.New("network failure")
+)
+
+// NetworkError represents a failure to get a response from the server.
+// It matches one of ErrTimeout, ErrDNS, ErrConnectionRefused, ErrTLS, ErrCanceled
+// and ErrNetwork with errors.Is, and wraps the underlying error.
+type NetworkError struct {
+	// Kind is the class of the failure, one of the sentinel errors above.
+	Kind error
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the string representation of the error.
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the error.
+func (e *NetworkError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Temporary reports whether retrying the request may succeed.
+func (e *NetworkError) Temporary() bool {
+	return e.Kind != ErrTLS && e.Kind != ErrCanceled
+}
+
+// classifyNetworkError wraps an error from the HTTP client in a NetworkError.
+func classifyNetworkError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &NetworkError{Kind: networkErrorKind(err), Err: err}
+}
+
+func networkErrorKind(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return ErrCanceled
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return ErrTimeout
+		}
+
+		return ErrDNS
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrConnectionRefused
+	}
+
+	if isTLSError(err) {
+		return ErrTLS
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+
+	return ErrNetwork
+}
+
+func isTLSError(err error) bool {
+	var (
+		unknownAuthorityErr x509.UnknownAuthorityError
+		certInvalidErr      x509.CertificateInvalidError
+		hostnameErr         x509.HostnameError
+		recordHeaderErr     tls.RecordHeaderError
+	)
+
+	switch {
+	case errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &certInvalidErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &recordHeaderErr):
+		return true
+	}
+
+	// Handshake failures reported by the server are plain errors prefixed with "tls: ".
+	return strings.Contains(err.Error(), "tls: ")
+}
@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestNetworkErrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsSrv.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	refusedURL := "http://" + l.Addr().String()
+	l.Close()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		url     string
+		client  *http.Client
+		expKind error
+	}{
+		{
+			name:    "should classify connection refused",
+			url:     refusedURL + "/api",
+			expKind: ErrConnectionRefused,
+		},
+		{
+			name:    "should classify timeout",
+			url:     slow.URL + "/api",
+			client:  &http.Client{Timeout: 20 * time.Millisecond},
+			expKind: ErrTimeout,
+		},
+		{
+			name:    "should classify cancelled context",
+			ctx:     cancelled,
+			url:     slow.URL + "/api",
+			expKind: ErrCanceled,
+		},
+		{
+			name:    "should classify tls failure",
+			url:     tlsSrv.URL + "/api",
+			expKind: ErrTLS,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			c := New(ctx, &Config{ClientID: "client-id", ClientSecret: "client-secret", AuthURL: slow.URL + "/token"}, tc.client)
+
+			// Fetch the token up front, so that the error comes from the API request.
+			if tc.ctx == nil {
+				if err := c.Authorize(ctx); err != nil {
+					t.Fatalf("error setting up test: %v", err)
+				}
+			}
+
+			_, sc, err := c.Request(ctx, &HTTPRequest{Method: http.MethodGet, URL: tc.url})
+
+			if !errors.Is(err, tc.expKind) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expKind)
+			}
+
+			var netErr *NetworkError
+			if !errors.As(err, &netErr) || netErr.Err == nil {
+				t.Errorf("expected NetworkError wrapping the cause: got %v", err)
+			}
+
+			if sc != 0 {
+				t.Errorf("unexpected status code: got %d, exp 0", sc)
+			}
+		})
+	}
+}
+
+func TestNetworkErrorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  error
+	}{
+		{name: "should classify dns error", err: &net.DNSError{Err: "no such host", Name: "publicapi.sbanken.invalid"}, exp: ErrDNS},
+		{name: "should classify dns timeout", err: &net.DNSError{Err: "timeout", IsTimeout: true}, exp: ErrTimeout},
+		{name: "should classify deadline", err: context.DeadlineExceeded, exp: ErrTimeout},
+		{name: "should classify tls alert", err: errors.New("remote error: tls: handshake failure"), exp: ErrTLS},
+		{name: "should fall back to network error", err: errors.New("EOF"), exp: ErrNetwork},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if kind := networkErrorKind(tc.err); kind != tc.exp {
+				t.Errorf("unexpected kind: got %v, exp %v", kind, tc.exp)
+			}
+		})
+	}
+}
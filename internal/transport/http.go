@@ -5,15 +5,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"time"
 )
 
 // HTTPRequest represents a http request.
 type HTTPRequest struct {
-	Method      string
-	URL         string
+	// Header holds additional request headers, set after the default headers.
+	Header http.Header
+	// Operation is the name of the logical operation, such as "ListAccounts" or "Transfer".
+	Operation string
+	Method    string
+	URL       string
+	// PostPayload is the JSON body of the request. It is required for POST, optional for PUT, PATCH and DELETE,
+	// and ignored for GET.
 	PostPayload []byte
+	// Stream is for optionally reading the body of a 200 OK response as it arrives, instead of buffering it.
+	// The body is not available to middleware, and a request is not retried once Stream has been called.
+	Stream func(body io.Reader) error
+}
+
+// clone returns a copy of the request, so that middleware can modify it without affecting later attempts.
+func (r *HTTPRequest) clone() *HTTPRequest {
+	c := *r
+	if r.Header != nil {
+		c.Header = r.Header.Clone()
+	}
+
+	return &c
 }
 
 // HTTPResponse represents a http response.
@@ -26,30 +46,125 @@ type HTTPResponse struct {
 	IsError        bool   `json:"isError"`
 }
 
-// Request performs the HTTP request.
+// Request performs the HTTP request, retrying according to the retry policy.
+// A request rejected with 401 Unauthorized discards the token, and is sent once more with a new
+// token if it may be sent again, i.e. it is idempotent or the policy has RetryNonIdempotent set.
+// The resent request does not count against MaxAttempts.
 func (c *Client) Request(ctx context.Context, r *HTTPRequest) ([]byte, int, error) {
-	token, err := c.getToken(ctx)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	var req *http.Request
-
 	switch r.Method {
-	case http.MethodGet:
-		req, err = http.NewRequest(r.Method, r.URL, nil)
+	case http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete:
 	case http.MethodPost:
 		if r.PostPayload == nil {
 			return nil, 0, errors.New("Post payload missing from POST")
 		}
-		req, err = http.NewRequest(r.Method, r.URL, bytes.NewBuffer(r.PostPayload))
-		req.Header.Set("Content-Type", "application/json")
 	default:
 		return nil, 0, fmt.Errorf("Invalid HTTP request method: %s", r.Method)
 	}
 
+	reauthorized := false
+	number := 0
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		data, sc, header, streamed, err := c.attempt(ctx, r)
+		number++
+
+		// The token may have expired or been revoked before its expiry, authorize again and resend once.
+		if sc == http.StatusUnauthorized && !streamed && !reauthorized {
+			reauthorized = true
+			c.tokens.invalidate()
+
+			if c.retry.mayResend(r.Method) {
+				c.retry.notify(Attempt{
+					Err:        err,
+					Method:     r.Method,
+					URL:        r.URL,
+					Number:     number,
+					StatusCode: sc,
+					Duration:   time.Since(start),
+					Retry:      true,
+				})
+
+				if c.logger != nil {
+					c.logger.Printf("sbanken: reauthorizing %s %s after attempt %d (StatusCode: %d): %v", r.Method, r.URL, number, sc, err)
+				}
+
+				attempt--
+				continue
+			}
+		}
+
+		retry, delay := false, time.Duration(0)
+		if !streamed {
+			retry, delay = c.retry.decide(attempt, r.Method, sc, header, err)
+		}
+
+		c.retry.notify(Attempt{
+			Err:        err,
+			Method:     r.Method,
+			URL:        r.URL,
+			Number:     number,
+			StatusCode: sc,
+			Duration:   time.Since(start),
+			Delay:      delay,
+			Retry:      retry,
+		})
+
+		if !retry {
+			return data, sc, err
+		}
+
+		if c.logger != nil {
+			c.logger.Printf("sbanken: retrying %s %s in %s after attempt %d (StatusCode: %d): %v", r.Method, r.URL, delay, number, sc, err)
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, sc, classifyNetworkError(err)
+		}
+	}
+}
+
+// attempt performs a single HTTP request through the middleware chain.
+// It reports whether the response body was passed to the Stream function of the request.
+func (c *Client) attempt(ctx context.Context, r *HTTPRequest) ([]byte, int, http.Header, bool, error) {
+	req := r.clone()
+
+	streamed := false
+	if r.Stream != nil {
+		req.Stream = func(body io.Reader) error {
+			streamed = true
+			return r.Stream(body)
+		}
+	}
+
+	res, err := c.handler(ctx, req)
+	if res == nil {
+		return nil, 0, nil, streamed, err
+	}
+
+	return res.Body, res.StatusCode, res.Header, streamed, err
+}
+
+// send is the innermost Handler, performing the HTTP request.
+func (c *Client) send(ctx context.Context, r *HTTPRequest) (*Response, error) {
+	token, err := c.getToken(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+
+	var req *http.Request
+
+	if r.PostPayload != nil && r.Method != http.MethodGet {
+		req, err = http.NewRequest(r.Method, r.URL, bytes.NewReader(r.PostPayload))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	} else {
+		req, err = http.NewRequest(r.Method, r.URL, nil)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	req = req.WithContext(ctx)
@@ -58,17 +173,42 @@ func (c *Client) Request(ctx context.Context, r *HTTPRequest) ([]byte, int, erro
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", c.userAgent)
 
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, classifyNetworkError(err)
+	}
+
+	start := time.Now()
+
 	res, err := c.http.Do(req)
 	if err != nil {
-		return nil, res.StatusCode, err
+		return nil, classifyNetworkError(err)
 	}
 
 	defer res.Body.Close()
 
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, res.StatusCode, err
+	response := &Response{
+		Header:     res.Header,
+		StatusCode: res.StatusCode,
+	}
+
+	if err := checkContentType(res); err != nil {
+		response.Latency = time.Since(start)
+		return response, err
 	}
 
-	return data, res.StatusCode, nil
+	if r.Stream != nil && res.StatusCode == http.StatusOK {
+		err := r.Stream(c.limitBody(res))
+		response.Latency = time.Since(start)
+
+		return response, err
+	}
+
+	response.Body, err = c.readBody(res)
+	response.Latency = time.Since(start)
+
+	return response, err
 }
@@ -0,0 +1,296 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestMethods(t *testing.T) {
+	var (
+		gotMethod      string
+		gotBody        string
+		gotContentType string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+
+		gotMethod = r.Method
+		gotBody = string(body)
+		gotContentType = r.Header.Get("Content-Type")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newTestRetryClient(srv, nil)
+
+	tests := []struct {
+		name           string
+		method         string
+		payload        []byte
+		expBody        string
+		expContentType string
+		expErr         bool
+	}{
+		{name: "should send GET without body", method: http.MethodGet, payload: []byte(`{"a":1}`)},
+		{name: "should send POST with body", method: http.MethodPost, payload: []byte(`{"a":1}`), expBody: `{"a":1}`, expContentType: "application/json"},
+		{name: "should require body for POST", method: http.MethodPost, expErr: true},
+		{name: "should send PUT with body", method: http.MethodPut, payload: []byte(`{"a":1}`), expBody: `{"a":1}`, expContentType: "application/json"},
+		{name: "should send PATCH with body", method: http.MethodPatch, payload: []byte(`{"a":1}`), expBody: `{"a":1}`, expContentType: "application/json"},
+		{name: "should send DELETE without body", method: http.MethodDelete},
+		{name: "should reject unsupported method", method: "CONNECT", expErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotMethod, gotBody, gotContentType = "", "", ""
+
+			_, sc, err := c.Request(context.Background(), &HTTPRequest{
+				Method:      tc.method,
+				URL:         srv.URL + "/api",
+				PostPayload: tc.payload,
+			})
+
+			if tc.expErr {
+				if err == nil {
+					t.Errorf("expected error for %s", tc.method)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sc != http.StatusOK || gotMethod != tc.method {
+				t.Errorf("unexpected request: got %s (%d), exp %s", gotMethod, sc, tc.method)
+			}
+
+			if gotBody != tc.expBody {
+				t.Errorf("unexpected body: got %q, exp %q", gotBody, tc.expBody)
+			}
+
+			if gotContentType != tc.expContentType {
+				t.Errorf("unexpected content type: got %q, exp %q", gotContentType, tc.expContentType)
+			}
+		})
+	}
+}
+
+func TestRequestStream(t *testing.T) {
+	errStream := errors.New("stream failed")
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		streamErr error
+		expData   string
+		expStream string
+		expCalls  int32
+		expErr    error
+	}{
+		{
+			name:      "should stream successful response",
+			statuses:  []int{200},
+			expStream: `{}`,
+			expCalls:  1,
+		},
+		{
+			name:     "should buffer error response",
+			statuses: []int{400},
+			expData:  `{}`,
+			expCalls: 1,
+		},
+		{
+			name:      "should retry before streaming",
+			statuses:  []int{503, 200},
+			expStream: `{}`,
+			expCalls:  2,
+		},
+		{
+			name:      "should not retry after streaming",
+			statuses:  []int{200},
+			streamErr: errStream,
+			expStream: `{}`,
+			expCalls:  1,
+			expErr:    errStream,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := newTestAPIServer(t, tc.statuses, nil)
+			c := newTestRetryClient(srv, &RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+				ShouldRetry: func(sc int, err error) bool { return err != nil || sc >= 500 },
+			})
+
+			var streamed string
+
+			data, _, err := c.Request(context.Background(), &HTTPRequest{
+				Method: http.MethodGet,
+				URL:    srv.URL + "/api",
+				Stream: func(body io.Reader) error {
+					b, _ := ioutil.ReadAll(body)
+					streamed = string(b)
+
+					return tc.streamErr
+				},
+			})
+
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if string(data) != tc.expData || streamed != tc.expStream {
+				t.Errorf("unexpected body: got %q/%q, exp %q/%q", data, streamed, tc.expData, tc.expStream)
+			}
+
+			if n := atomic.LoadInt32(calls); n != tc.expCalls {
+				t.Errorf("unexpected number of calls: got %d, exp %d", n, tc.expCalls)
+			}
+		})
+	}
+}
+
+func TestRequestReauthorize(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		policy      *RetryPolicy
+		statuses    []int
+		expStatus   int
+		expCalls    int32
+		expTokens   int32
+		expAttempts []Attempt
+	}{
+		{
+			name:        "should not reauthorize on success",
+			method:      http.MethodGet,
+			policy:      &RetryPolicy{},
+			statuses:    []int{200},
+			expStatus:   200,
+			expCalls:    1,
+			expTokens:   1,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 200}},
+		},
+		{
+			name:        "should reauthorize and resend once on 401",
+			method:      http.MethodGet,
+			policy:      &RetryPolicy{},
+			statuses:    []int{401, 200},
+			expStatus:   200,
+			expCalls:    2,
+			expTokens:   2,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 401, Retry: true}, {Number: 2, StatusCode: 200}},
+		},
+		{
+			name:        "should give up when new token is rejected",
+			method:      http.MethodGet,
+			policy:      &RetryPolicy{},
+			statuses:    []int{401, 401, 200},
+			expStatus:   401,
+			expCalls:    2,
+			expTokens:   2,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 401, Retry: true}, {Number: 2, StatusCode: 401}},
+		},
+		{
+			name:        "should not count reauthorization against max attempts",
+			method:      http.MethodGet,
+			policy:      &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+			statuses:    []int{401, 503, 200},
+			expStatus:   200,
+			expCalls:    3,
+			expTokens:   2,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 401, Retry: true}, {Number: 2, StatusCode: 503, Retry: true}, {Number: 3, StatusCode: 200}},
+		},
+		{
+			name:        "should not resend POST on 401",
+			method:      http.MethodPost,
+			policy:      &RetryPolicy{},
+			statuses:    []int{401, 200},
+			expStatus:   401,
+			expCalls:    1,
+			expTokens:   1,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 401}},
+		},
+		{
+			name:        "should resend POST on 401 when non-idempotent retries are enabled",
+			method:      http.MethodPost,
+			policy:      &RetryPolicy{RetryNonIdempotent: true},
+			statuses:    []int{401, 200},
+			expStatus:   200,
+			expCalls:    2,
+			expTokens:   2,
+			expAttempts: []Attempt{{Number: 1, StatusCode: 401, Retry: true}, {Number: 2, StatusCode: 200}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := newTestAPIServer(t, tc.statuses, nil)
+
+			var attempts []Attempt
+			tc.policy.OnAttempt = func(a Attempt) {
+				attempts = append(attempts, Attempt{Number: a.Number, StatusCode: a.StatusCode, Retry: a.Retry})
+			}
+
+			c := newTestRetryClient(srv, tc.policy)
+
+			var tokens int32
+			fetch := c.tokens.fetch
+			c.tokens.fetch = func(ctx context.Context) (*Token, error) {
+				atomic.AddInt32(&tokens, 1)
+				return fetch(ctx)
+			}
+
+			r := &HTTPRequest{Method: tc.method, URL: srv.URL + "/api"}
+			if tc.method == http.MethodPost {
+				r.PostPayload = []byte("{}")
+			}
+
+			_, sc, err := c.Request(context.Background(), r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sc != tc.expStatus {
+				t.Errorf("unexpected status code: got %d, exp %d", sc, tc.expStatus)
+			}
+
+			if n := atomic.LoadInt32(calls); n != tc.expCalls {
+				t.Errorf("unexpected number of calls: got %d, exp %d", n, tc.expCalls)
+			}
+
+			if n := atomic.LoadInt32(&tokens); n != tc.expTokens {
+				t.Errorf("unexpected number of token requests: got %d, exp %d", n, tc.expTokens)
+			}
+
+			if len(attempts) != len(tc.expAttempts) {
+				t.Fatalf("unexpected attempts: got %+v, exp %+v", attempts, tc.expAttempts)
+			}
+
+			for i := range attempts {
+				if attempts[i] != tc.expAttempts[i] {
+					t.Errorf("unexpected attempt %d: got %+v, exp %+v", i+1, attempts[i], tc.expAttempts[i])
+				}
+			}
+		})
+	}
+}
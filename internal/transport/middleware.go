@@ -0,0 +1,41 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Handler performs a single attempt of a request.
+//
+// A Handler may return both a response and an error, for instance when the
+// response body could not be read.
+type Handler func(ctx context.Context, r *HTTPRequest) (*Response, error)
+
+// Middleware wraps a Handler to add behaviour such as logging, metrics or
+// header injection. It may inspect and modify the request before calling next,
+// inspect the response afterwards, or return a response without calling next.
+type Middleware func(next Handler) Handler
+
+// Response represents the response of a single attempt of a request.
+type Response struct {
+	// Header is the response header.
+	Header http.Header
+	// Body is the raw response body. It is nil when the body was streamed, see HTTPRequest.Stream.
+	Body []byte
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Latency is the time from sending the request until the response body was read.
+	Latency time.Duration
+}
+
+// chain wraps h in the middleware, so that the first middleware is the outermost.
+func chain(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			h = middleware[i](h)
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,152 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+
+		if r.Header.Get("X-Audit") != "audit-id" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var order []string
+
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, r *HTTPRequest) (*Response, error) {
+				order = append(order, name+" before")
+				res, err := next(ctx, r)
+				order = append(order, name+" after")
+
+				return res, err
+			}
+		}
+	}
+
+	var seen *Response
+	var seenOperation string
+
+	inspect := func(next Handler) Handler {
+		return func(ctx context.Context, r *HTTPRequest) (*Response, error) {
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+
+			r.Header.Set("X-Audit", "audit-id")
+			seenOperation = r.Operation
+
+			res, err := next(ctx, r)
+			seen = res
+
+			return res, err
+		}
+	}
+
+	c := New(context.Background(), &Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		AuthURL:      srv.URL + "/token",
+		Middleware:   []Middleware{record("outer"), nil, record("inner"), inspect},
+	}, srv.Client())
+
+	r := &HTTPRequest{Operation: "ListAccounts", Method: http.MethodGet, URL: srv.URL + "/api"}
+
+	data, sc, err := c.Request(context.Background(), r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("should inject headers", func(t *testing.T) {
+		if sc != http.StatusOK || string(data) != `{"ok":true}` {
+			t.Errorf("unexpected response: got %d %s", sc, data)
+		}
+
+		if r.Header != nil {
+			t.Errorf("expected original request to be unmodified: got %v", r.Header)
+		}
+	})
+
+	t.Run("should see operation and response", func(t *testing.T) {
+		if seenOperation != "ListAccounts" {
+			t.Errorf("unexpected operation: got %q", seenOperation)
+		}
+
+		if seen == nil || seen.StatusCode != http.StatusOK || string(seen.Body) != `{"ok":true}` || seen.Latency <= 0 {
+			t.Errorf("unexpected response: got %+v", seen)
+		}
+	})
+
+	t.Run("should call middleware in order", func(t *testing.T) {
+		exp := []string{"outer before", "inner before", "inner after", "outer after"}
+		if len(order) != len(exp) {
+			t.Fatalf("unexpected order: got %v, exp %v", order, exp)
+		}
+
+		for i := range exp {
+			if order[i] != exp[i] {
+				t.Errorf("unexpected order: got %v, exp %v", order, exp)
+				break
+			}
+		}
+	})
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	srv, calls := newTestAPIServer(t, []int{200}, nil)
+
+	errInjected := errors.New("injected")
+	var attempts int32
+
+	chaos := func(next Handler) Handler {
+		return func(ctx context.Context, r *HTTPRequest) (*Response, error) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return nil, &NetworkError{Kind: ErrNetwork, Err: errInjected}
+			}
+
+			return next(ctx, r)
+		}
+	}
+
+	c := New(context.Background(), &Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		AuthURL:      srv.URL + "/token",
+		RetryPolicy:  &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Middleware:   []Middleware{chaos},
+	}, srv.Client())
+
+	_, sc, err := c.Request(context.Background(), &HTTPRequest{Method: http.MethodGet, URL: srv.URL + "/api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sc != http.StatusOK {
+		t.Errorf("unexpected status code: got %d, exp %d", sc, http.StatusOK)
+	}
+
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("unexpected number of calls: got %d, exp 1", n)
+	}
+
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("unexpected number of attempts: got %d, exp 2", n)
+	}
+}
@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter. It is safe for concurrent use,
+// and can be shared between clients to limit their combined request rate.
+type RateLimiter struct {
+	now    func() time.Time
+	last   time.Time
+	rate   float64
+	burst  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+// NewRateLimiter returns a rate limiter allowing rps requests per second on average,
+// with bursts of up to burst requests. A burst below 1 is treated as 1.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		now:    time.Now,
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// sharedRateLimiterKey identifies the clients sharing a rate limiter.
+type sharedRateLimiterKey struct {
+	clientID string
+	rps      float64
+	burst    int
+}
+
+var (
+	sharedRateLimitersMu sync.Mutex
+	sharedRateLimiters   = map[sharedRateLimiterKey]*RateLimiter{}
+)
+
+// SharedRateLimiter returns the rate limiter shared by all clients with the given client ID, rps and burst.
+// Clients with the same client ID but other limits get a limiter of their own. An empty client ID, as with
+// a custom token source, can not identify the clients to share with, so a new limiter is returned.
+//
+// The limiters are kept for the lifetime of the process, one for each combination of client ID and limits.
+func SharedRateLimiter(clientID string, rps float64, burst int) *RateLimiter {
+	if clientID == "" {
+		return NewRateLimiter(rps, burst)
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	key := sharedRateLimiterKey{clientID: clientID, rps: rps, burst: burst}
+
+	sharedRateLimitersMu.Lock()
+	defer sharedRateLimitersMu.Unlock()
+
+	if l, ok := sharedRateLimiters[key]; ok {
+		return l
+	}
+
+	l := NewRateLimiter(rps, burst)
+	sharedRateLimiters[key] = l
+
+	return l
+}
+
+// Wait blocks until a request is allowed, or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.refill(now)
+
+	// Reserve a token up front, so that concurrent waiters queue up behind each other.
+	l.tokens--
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(math.Ceil(-l.tokens / l.rate * float64(time.Second)))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		l.cancel()
+		return context.DeadlineExceeded
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		l.cancel()
+		return err
+	}
+
+	return nil
+}
+
+// cancel returns a reserved token that was not used.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// refill adds tokens for the time passed since the last refill. The caller must hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+
+	l.last = now
+}
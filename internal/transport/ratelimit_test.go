@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(20, 3)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("burst should not wait: waited %s", d)
+	}
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("request after burst should wait: waited %s", d)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(100, 1)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := l.Wait(ctx); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Errorf("10 requests at 100 rps with burst 1 should take at least 90ms: took %s", d)
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	t.Run("should fail early when deadline is too close", func(t *testing.T) {
+		l := NewRateLimiter(1, 1)
+		l.Wait(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if err := l.Wait(ctx); err != context.DeadlineExceeded {
+			t.Errorf("unexpected error: got %v, exp %v", err, context.DeadlineExceeded)
+		}
+
+		if d := time.Since(start); d > 5*time.Millisecond {
+			t.Errorf("should not wait for deadline: waited %s", d)
+		}
+	})
+
+	t.Run("should stop waiting when cancelled", func(t *testing.T) {
+		l := NewRateLimiter(1, 1)
+		l.Wait(context.Background())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		if err := l.Wait(ctx); err != context.Canceled {
+			t.Errorf("unexpected error: got %v, exp %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("should not limit nil limiter", func(t *testing.T) {
+		var l *RateLimiter
+		if err := l.Wait(context.Background()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         func() *RateLimiter
+		b         func() *RateLimiter
+		expShared bool
+	}{
+		{
+			name:      "should share limiter with same client ID and limits",
+			a:         func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			b:         func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			expShared: true,
+		},
+		{
+			name:      "should treat burst below one as one",
+			a:         func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 0) },
+			b:         func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			expShared: true,
+		},
+		{
+			name: "should not share limiter with other rate",
+			a:    func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			b:    func() *RateLimiter { return SharedRateLimiter("shared-client-id", 10, 1) },
+		},
+		{
+			name: "should not share limiter with other burst",
+			a:    func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			b:    func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 2) },
+		},
+		{
+			name: "should not share limiter with other client ID",
+			a:    func() *RateLimiter { return SharedRateLimiter("shared-client-id", 5, 1) },
+			b:    func() *RateLimiter { return SharedRateLimiter("other-client-id", 5, 1) },
+		},
+		{
+			name: "should not share limiter without client ID",
+			a:    func() *RateLimiter { return SharedRateLimiter("", 5, 1) },
+			b:    func() *RateLimiter { return SharedRateLimiter("", 5, 1) },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if shared := tc.a() == tc.b(); shared != tc.expShared {
+				t.Errorf("unexpected sharing: got %t, exp %t", shared, tc.expShared)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodySize is the maximum size of a response body when no limit is set.
+const DefaultMaxBodySize = 10 << 20
+
+// maxSnippetSize is the maximum size of the body snippet in an UnexpectedResponseError.
+const maxSnippetSize = 256
+
+var (
+	// ErrUnexpectedContentType are returned when the response is not JSON, such as an HTML error page from a proxy.
+	ErrUnexpectedContentType = errors.New("unexpected content type")
+	// ErrResponseTooLarge are returned when the response body exceeds the maximum body size.
+	ErrResponseTooLarge = errors.New("response body too large")
+)
+
+// UnexpectedResponseError represents a response that could not be handled as an API response.
+// It matches one of ErrUnexpectedContentType and ErrResponseTooLarge with errors.Is.
+type UnexpectedResponseError struct {
+	// Kind is the class of the failure, one of the sentinel errors above.
+	Kind error
+	// ContentType is the Content-Type header of the response.
+	ContentType string
+	// Snippet is the start of the response body, truncated to a few hundred bytes.
+	Snippet string
+	// StatusCode is the status code of the response.
+	StatusCode int
+}
+
+// Error returns the string representation of the error.
+func (e *UnexpectedResponseError) Error() string {
+	str := fmt.Sprintf("%s (StatusCode: %d / Content-Type: %q)", e.Kind, e.StatusCode, e.ContentType)
+
+	if e.Snippet != "" {
+		str = fmt.Sprintf("%s: %s", str, e.Snippet)
+	}
+
+	return str
+}
+
+// Is reports whether target is the kind of the error.
+func (e *UnexpectedResponseError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func newUnexpectedResponseError(kind error, res *http.Response, body []byte) *UnexpectedResponseError {
+	return &UnexpectedResponseError{
+		Kind:        kind,
+		ContentType: res.Header.Get("Content-Type"),
+		Snippet:     snippet(body),
+		StatusCode:  res.StatusCode,
+	}
+}
+
+// checkContentType returns an error if the response has a body that is not JSON.
+// Responses without a Content-Type header are accepted unless the body looks like markup.
+func checkContentType(res *http.Response) error {
+	ct := res.Header.Get("Content-Type")
+	if ct == "" {
+		return sniffBody(res)
+	}
+
+	if isJSON(ct) {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxSnippetSize+1))
+	if err != nil {
+		return classifyNetworkError(err)
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	return newUnexpectedResponseError(ErrUnexpectedContentType, res, body)
+}
+
+// sniffBody returns an error if the body starts with '<', such as an HTML error page served
+// without a Content-Type. The start of the body is put back so that it can still be read.
+func sniffBody(res *http.Response) error {
+	start, err := ioutil.ReadAll(io.LimitReader(res.Body, maxSnippetSize+1))
+	if err != nil {
+		return classifyNetworkError(err)
+	}
+
+	if trimmed := bytes.TrimLeft(start, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '<' {
+		return newUnexpectedResponseError(ErrUnexpectedContentType, res, start)
+	}
+
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(start), res.Body), res.Body}
+
+	return nil
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// readBody reads the response body, returning an error if it exceeds the maximum body size.
+func (c *Client) readBody(res *http.Response) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(res.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, classifyNetworkError(err)
+	}
+
+	if int64(len(data)) > c.maxBodySize {
+		return nil, newUnexpectedResponseError(ErrResponseTooLarge, res, data)
+	}
+
+	return data, nil
+}
+
+// limitBody returns a reader of the response body failing when it exceeds the maximum body size.
+func (c *Client) limitBody(res *http.Response) io.Reader {
+	return &limitedBody{res: res, n: c.maxBodySize}
+}
+
+type limitedBody struct {
+	res *http.Response
+	n   int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.n < 0 {
+		return 0, newUnexpectedResponseError(ErrResponseTooLarge, b.res, nil)
+	}
+
+	// Read one byte more than allowed to detect bodies exceeding the limit.
+	if int64(len(p)) > b.n+1 {
+		p = p[:b.n+1]
+	}
+
+	n, err := b.res.Body.Read(p)
+	b.n -= int64(n)
+
+	if b.n < 0 {
+		return n + int(b.n), newUnexpectedResponseError(ErrResponseTooLarge, b.res, nil)
+	}
+
+	if err != nil && err != io.EOF {
+		err = classifyNetworkError(err)
+	}
+
+	return n, err
+}
+
+// snippet returns the start of the body as a string, truncated to maxSnippetSize bytes.
+func snippet(body []byte) string {
+	truncated := len(body) > maxSnippetSize
+	if truncated {
+		body = body[:maxSnippetSize]
+	}
+
+	// A multi-byte character cut in half is replaced.
+	s := strings.ToValidUTF8(strings.TrimSpace(string(body)), "�")
+	if truncated {
+		s += "..."
+	}
+
+	return s
+}
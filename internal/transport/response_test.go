@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		maxBodySize int64
+		stream      bool
+		expErr      error
+		expSnippet  string
+	}{
+		{
+			name:        "should accept JSON",
+			contentType: "application/json; charset=utf-8",
+			body:        `{}`,
+			status:      http.StatusOK,
+		},
+		{
+			name:        "should accept JSON based media types",
+			contentType: "application/problem+json",
+			body:        `{}`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:   "should accept missing content type",
+			body:   `{}`,
+			status: http.StatusOK,
+		},
+		{
+			name:       "should reject HTML without content type",
+			body:       "\n  <html><body>Bad Gateway</body></html>",
+			status:     http.StatusBadGateway,
+			expErr:     ErrUnexpectedContentType,
+			expSnippet: "<html><body>Bad Gateway</body></html>",
+		},
+		{
+			name:   "should stream body without content type",
+			body:   `{"items":[]}`,
+			status: http.StatusOK,
+			stream: true,
+		},
+		{
+			name:        "should accept empty body with other content type",
+			contentType: "text/html",
+			status:      http.StatusUnauthorized,
+		},
+		{
+			name:        "should reject HTML error page",
+			contentType: "text/html; charset=utf-8",
+			body:        "<html><body>Bad Gateway</body></html>",
+			status:      http.StatusBadGateway,
+			expErr:      ErrUnexpectedContentType,
+			expSnippet:  "<html><body>Bad Gateway</body></html>",
+		},
+		{
+			name:        "should truncate snippet",
+			contentType: "text/plain",
+			body:        strings.Repeat("a", 1000),
+			status:      http.StatusOK,
+			expErr:      ErrUnexpectedContentType,
+			expSnippet:  strings.Repeat("a", maxSnippetSize) + "...",
+		},
+		{
+			name:        "should reject body exceeding limit",
+			contentType: "application/json",
+			body:        `{"items":[1,2,3]}`,
+			status:      http.StatusOK,
+			maxBodySize: 10,
+			expErr:      ErrResponseTooLarge,
+			expSnippet:  `{"items":[1`,
+		},
+		{
+			name:        "should accept body at limit",
+			contentType: "application/json",
+			body:        `{"items":[]}`,
+			status:      http.StatusOK,
+			maxBodySize: 12,
+		},
+		{
+			name:        "should reject streamed body exceeding limit",
+			contentType: "application/json",
+			body:        `{"items":[1,2,3]}`,
+			status:      http.StatusOK,
+			maxBodySize: 10,
+			stream:      true,
+			expErr:      ErrResponseTooLarge,
+		},
+		{
+			name:        "should reject streamed HTML",
+			contentType: "text/html",
+			body:        "<html></html>",
+			status:      http.StatusOK,
+			stream:      true,
+			expErr:      ErrUnexpectedContentType,
+			expSnippet:  "<html></html>",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+					return
+				}
+
+				w.Header()["Content-Type"] = []string{tc.contentType}
+				if tc.contentType == "" {
+					w.Header()["Content-Type"] = nil
+				}
+
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			c := New(context.Background(), &Config{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				AuthURL:      srv.URL + "/token",
+				MaxBodySize:  tc.maxBodySize,
+			}, srv.Client())
+
+			r := &HTTPRequest{Method: http.MethodGet, URL: srv.URL + "/api"}
+
+			var streamed string
+			if tc.stream {
+				r.Stream = func(body io.Reader) error {
+					b, err := ioutil.ReadAll(body)
+					streamed = string(b)
+
+					return err
+				}
+			}
+
+			data, sc, err := c.Request(context.Background(), r)
+
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if sc != tc.status {
+				t.Errorf("unexpected status code: got %d, exp %d", sc, tc.status)
+			}
+
+			if tc.expErr == nil {
+				if string(data) != tc.body && streamed != tc.body {
+					t.Errorf("unexpected body: got %q, exp %q", data, tc.body)
+				}
+
+				return
+			}
+
+			var unexpectedErr *UnexpectedResponseError
+			if !errors.As(err, &unexpectedErr) {
+				t.Fatalf("expected UnexpectedResponseError: got %T", err)
+			}
+
+			if unexpectedErr.StatusCode != tc.status || unexpectedErr.ContentType != tc.contentType {
+				t.Errorf("unexpected error details: got %+v", unexpectedErr)
+			}
+
+			if unexpectedErr.Snippet != tc.expSnippet {
+				t.Errorf("unexpected snippet: got %q, exp %q", unexpectedErr.Snippet, tc.expSnippet)
+			}
+
+			if tc.stream && int64(len(streamed)) > c.maxBodySize {
+				t.Errorf("streamed more than the limit: got %d bytes", len(streamed))
+			}
+		})
+	}
+}
+
+func TestResponseValidationRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		maxBodySize int64
+		expCalls    int32
+	}{
+		{name: "should retry HTML error page with 5xx status", status: http.StatusServiceUnavailable, contentType: "text/html", expCalls: 3},
+		{name: "should not retry HTML page with 4xx status", status: http.StatusForbidden, contentType: "text/html", expCalls: 1},
+		{name: "should not retry body exceeding limit", status: http.StatusServiceUnavailable, contentType: "application/json", maxBodySize: 1, expCalls: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+					return
+				}
+
+				atomic.AddInt32(&calls, 1)
+
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(tc.status)
+				w.Write([]byte("<html>error</html>"))
+			}))
+			t.Cleanup(srv.Close)
+
+			c := New(context.Background(), &Config{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				AuthURL:      srv.URL + "/token",
+				MaxBodySize:  tc.maxBodySize,
+				RetryPolicy:  &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			}, srv.Client())
+
+			if _, _, err := c.Request(context.Background(), &HTTPRequest{Method: http.MethodGet, URL: srv.URL + "/api"}); err == nil {
+				t.Fatal("expected error")
+			}
+
+			if n := atomic.LoadInt32(&calls); n != tc.expCalls {
+				t.Errorf("unexpected number of calls: got %d, exp %d", n, tc.expCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetryBaseDelay is the delay before the first retry when no base delay is set.
+	DefaultRetryBaseDelay = 200 * time.Millisecond
+	// DefaultRetryMaxDelay is the maximum backoff delay between attempts when no max delay is set.
+	DefaultRetryMaxDelay = 10 * time.Second
+)
+
+// RetryPolicy configures retries of failed requests.
+//
+// Only idempotent requests (GET, HEAD, PUT, DELETE and OPTIONS) are retried,
+// unless RetryNonIdempotent is set. The delay between attempts grows
+// exponentially from BaseDelay up to MaxDelay. A Retry-After header in the
+// response takes precedence over the computed delay, but is also capped by
+// MaxDelay, so that a server can not block a request for hours.
+type RetryPolicy struct {
+	// ShouldRetry decides whether an attempt should be retried based on its status code or error.
+	// Defaults to DefaultShouldRetry.
+	ShouldRetry func(statusCode int, err error) bool
+	// OnAttempt is called after every attempt, including the last one.
+	OnAttempt func(Attempt)
+	// MaxAttempts is the maximum number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. Defaults to DefaultRetryBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including Retry-After delays. Defaults to DefaultRetryMaxDelay.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1.
+	Jitter float64
+	// RetryNonIdempotent enables retries of non-idempotent requests, such as POST.
+	// A retried transfer or payment may be executed twice. It also enables sending
+	// them once more with a new token after 401 Unauthorized.
+	RetryNonIdempotent bool
+}
+
+// Attempt describes a single attempt of a request.
+type Attempt struct {
+	// Err is the error of the attempt, if any.
+	Err error
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the URL of the request.
+	URL string
+	// Number is the attempt number, starting at 1.
+	Number int
+	// StatusCode is the status code of the response, or 0 if no response was received.
+	StatusCode int
+	// Duration is how long the attempt took.
+	Duration time.Duration
+	// Delay is how long the client waits before the next attempt.
+	Delay time.Duration
+	// Retry reports whether the request will be retried.
+	Retry bool
+}
+
+// DefaultShouldRetry retries temporary network and authorization errors,
+// 429 Too Many Requests and 5xx responses except 501 Not Implemented.
+// Cancelled requests, TLS failures and other errors, such as invalid URLs
+// or missing tokens, are not retried.
+func DefaultShouldRetry(statusCode int, err error) bool {
+	if err != nil {
+		var authErr *AuthError
+		if errors.As(err, &authErr) {
+			return authErr.Temporary()
+		}
+
+		var netErr *NetworkError
+		if errors.As(err, &netErr) {
+			return netErr.Temporary() && !isContextError(err)
+		}
+
+		var unexpectedErr *UnexpectedResponseError
+		if !errors.As(err, &unexpectedErr) {
+			return false
+		}
+
+		// Unexpected responses, such as HTML error pages from a proxy, are retried based on their status code.
+		if unexpectedErr.Kind == ErrResponseTooLarge {
+			return false
+		}
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// decide returns whether the attempt should be retried, and how long to wait before the next attempt.
+func (p *RetryPolicy) decide(attempt int, method string, statusCode int, header http.Header, err error) (bool, time.Duration) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false, 0
+	}
+
+	if !p.mayResend(method) {
+		return false, 0
+	}
+
+	shouldRetry := p.ShouldRetry
+	if shouldRetry == nil {
+		shouldRetry = DefaultShouldRetry
+	}
+
+	if !shouldRetry(statusCode, err) {
+		return false, 0
+	}
+
+	if d, ok := parseRetryAfter(header, time.Now()); ok {
+		if max := p.maxDelay(); d > max {
+			d = max
+		}
+
+		return true, d
+	}
+
+	return true, p.backoff(attempt)
+}
+
+// backoff returns the delay after the given attempt, with jitter applied.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+
+	max := p.maxDelay()
+
+	d := float64(base) * math.Pow(2, float64(attempt-1))
+	if d > float64(max) {
+		d = float64(max)
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+
+		d -= d * jitter * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// maxDelay returns the maximum delay between attempts.
+func (p *RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return DefaultRetryMaxDelay
+	}
+
+	return p.MaxDelay
+}
+
+// mayResend reports whether a request with the given method may be sent again. Non-idempotent
+// requests are only sent again if RetryNonIdempotent is set.
+func (p *RetryPolicy) mayResend(method string) bool {
+	return isIdempotent(method) || (p != nil && p.RetryNonIdempotent)
+}
+
+func (p *RetryPolicy) notify(a Attempt) {
+	if p != nil && p.OnAttempt != nil {
+		p.OnAttempt(a)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+
+		// Avoid overflowing time.Duration with absurd values.
+		if secs > int(math.MaxInt64/int64(time.Second)) {
+			return math.MaxInt64, true
+		}
+
+		return time.Duration(secs) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	d := t.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+
+	return d, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
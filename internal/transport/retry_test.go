@@ -0,0 +1,321 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestAPIServer serves tokens on /token and responds with the given status codes in order on every other path.
+func newTestAPIServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+
+		n := int(atomic.AddInt32(&calls, 1))
+		sc := statuses[len(statuses)-1]
+		if n <= len(statuses) {
+			sc = statuses[n-1]
+		}
+
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(sc)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newTestRetryClient(srv *httptest.Server, p *RetryPolicy) *Client {
+	return New(context.Background(), &Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		AuthURL:      srv.URL + "/token",
+		RetryPolicy:  p,
+	}, srv.Client())
+}
+
+func TestRequestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		header    http.Header
+		method    string
+		policy    *RetryPolicy
+		expStatus int
+		expCalls  int32
+	}{
+		{
+			name:      "should not retry without policy",
+			statuses:  []int{503, 200},
+			method:    http.MethodGet,
+			expStatus: 503,
+			expCalls:  1,
+		},
+		{
+			name:      "should retry GET on 5xx",
+			statuses:  []int{503, 502, 200},
+			method:    http.MethodGet,
+			policy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			expStatus: 200,
+			expCalls:  3,
+		},
+		{
+			name:      "should give up after max attempts",
+			statuses:  []int{500},
+			method:    http.MethodGet,
+			policy:    &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+			expStatus: 500,
+			expCalls:  2,
+		},
+		{
+			name:      "should not retry 4xx",
+			statuses:  []int{400, 200},
+			method:    http.MethodGet,
+			policy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			expStatus: 400,
+			expCalls:  1,
+		},
+		{
+			name:      "should retry 429 with Retry-After",
+			statuses:  []int{429, 200},
+			header:    http.Header{"Retry-After": []string{"0"}},
+			method:    http.MethodGet,
+			policy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour},
+			expStatus: 200,
+			expCalls:  2,
+		},
+		{
+			name:      "should not retry POST by default",
+			statuses:  []int{503, 200},
+			method:    http.MethodPost,
+			policy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			expStatus: 503,
+			expCalls:  1,
+		},
+		{
+			name:      "should retry POST when opted in",
+			statuses:  []int{503, 200},
+			method:    http.MethodPost,
+			policy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true},
+			expStatus: 200,
+			expCalls:  2,
+		},
+		{
+			name:     "should use custom retry decision",
+			statuses: []int{409, 200},
+			method:   http.MethodGet,
+			policy: &RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+				ShouldRetry: func(sc int, err error) bool { return sc == http.StatusConflict },
+			},
+			expStatus: 200,
+			expCalls:  2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := newTestAPIServer(t, tc.statuses, tc.header)
+			c := newTestRetryClient(srv, tc.policy)
+
+			_, sc, err := c.Request(context.Background(), &HTTPRequest{
+				Method:      tc.method,
+				URL:         srv.URL + "/api",
+				PostPayload: []byte(`{}`),
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sc != tc.expStatus {
+				t.Errorf("unexpected status code: got %d, exp %d", sc, tc.expStatus)
+			}
+
+			if n := atomic.LoadInt32(calls); n != tc.expCalls {
+				t.Errorf("unexpected number of calls: got %d, exp %d", n, tc.expCalls)
+			}
+		})
+	}
+}
+
+func TestRequestRetryHook(t *testing.T) {
+	srv, _ := newTestAPIServer(t, []int{503, 503, 200}, nil)
+
+	var attempts []Attempt
+	c := newTestRetryClient(srv, &RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		OnAttempt:   func(a Attempt) { attempts = append(attempts, a) },
+	})
+
+	if _, _, err := c.Request(context.Background(), &HTTPRequest{Method: http.MethodGet, URL: srv.URL + "/api"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("unexpected number of attempts: got %d, exp 3", len(attempts))
+	}
+
+	for i, a := range attempts {
+		if a.Number != i+1 {
+			t.Errorf("unexpected attempt number: got %d, exp %d", a.Number, i+1)
+		}
+
+		if a.Retry != (i < 2) {
+			t.Errorf("unexpected retry for attempt %d: got %t", a.Number, a.Retry)
+		}
+	}
+
+	if attempts[0].StatusCode != 503 || attempts[2].StatusCode != 200 {
+		t.Errorf("unexpected status codes: %d, %d", attempts[0].StatusCode, attempts[2].StatusCode)
+	}
+}
+
+func TestRequestRetryContextCancelled(t *testing.T) {
+	srv, calls := newTestAPIServer(t, []int{503}, nil)
+	c := newTestRetryClient(srv, &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err := c.Request(ctx, &HTTPRequest{Method: http.MethodGet, URL: srv.URL + "/api"})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrTimeout) {
+		t.Errorf("unexpected error: got %v, exp %v", err, ErrTimeout)
+	}
+
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("unexpected number of calls: got %d, exp 1", n)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		exp     time.Duration
+	}{
+		{attempt: 1, exp: 100 * time.Millisecond},
+		{attempt: 2, exp: 200 * time.Millisecond},
+		{attempt: 4, exp: 800 * time.Millisecond},
+		{attempt: 5, exp: time.Second},
+	}
+
+	for _, tc := range tests {
+		if d := p.backoff(tc.attempt); d != tc.exp {
+			t.Errorf("unexpected backoff for attempt %d: got %s, exp %s", tc.attempt, d, tc.exp)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.backoff(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("backoff with jitter out of range: %s", d)
+		}
+	}
+}
+
+func TestRetryAfterMaxDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *RetryPolicy
+		value  string
+		exp    time.Duration
+	}{
+		{name: "should cap Retry-After by MaxDelay", policy: &RetryPolicy{MaxAttempts: 2, MaxDelay: time.Second}, value: "3600", exp: time.Second},
+		{name: "should keep Retry-After below MaxDelay", policy: &RetryPolicy{MaxAttempts: 2, MaxDelay: time.Minute}, value: "5", exp: 5 * time.Second},
+		{name: "should cap Retry-After by default max delay", policy: &RetryPolicy{MaxAttempts: 2}, value: "86400", exp: DefaultRetryMaxDelay},
+		{name: "should cap huge Retry-After by default max delay", policy: &RetryPolicy{MaxAttempts: 2}, value: "99999999999999999", exp: DefaultRetryMaxDelay},
+		{name: "should keep Retry-After below default max delay", policy: &RetryPolicy{MaxAttempts: 2}, value: "5", exp: 5 * time.Second},
+		{name: "should cap Retry-After date by MaxDelay", policy: &RetryPolicy{MaxAttempts: 2, MaxDelay: time.Second}, value: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), exp: time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			retry, d := tc.policy.decide(1, http.MethodGet, http.StatusTooManyRequests, http.Header{"Retry-After": {tc.value}}, nil)
+			if !retry || d != tc.exp {
+				t.Errorf("unexpected result: got %t/%s, exp true/%s", retry, d, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		exp        bool
+	}{
+		{name: "should retry 503", statusCode: http.StatusServiceUnavailable, exp: true},
+		{name: "should retry 429", statusCode: http.StatusTooManyRequests, exp: true},
+		{name: "should not retry 501", statusCode: http.StatusNotImplemented},
+		{name: "should not retry 400", statusCode: http.StatusBadRequest},
+		{name: "should retry connection refused", err: &NetworkError{Kind: ErrConnectionRefused, Err: errors.New("refused")}, exp: true},
+		{name: "should not retry cancelled request", err: &NetworkError{Kind: ErrCanceled, Err: context.Canceled}},
+		{name: "should not retry missing token", err: ErrMissingToken},
+		{name: "should not retry invalid URL", err: &url.Error{Op: "parse", URL: ":", Err: errors.New("missing protocol scheme")}},
+		{name: "should retry HTML error page by status code", statusCode: http.StatusBadGateway, err: &UnexpectedResponseError{Kind: ErrUnexpectedContentType, StatusCode: http.StatusBadGateway}, exp: true},
+		{name: "should not retry too large response", statusCode: http.StatusOK, err: &UnexpectedResponseError{Kind: ErrResponseTooLarge}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DefaultShouldRetry(tc.statusCode, tc.err); got != tc.exp {
+				t.Errorf("unexpected result: got %t, exp %t", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		exp   time.Duration
+		expOK bool
+	}{
+		{name: "should handle missing header"},
+		{name: "should parse seconds", value: "120", exp: 2 * time.Minute, expOK: true},
+		{name: "should parse HTTP date", value: "Sun, 31 Jan 2021 12:00:30 GMT", exp: 30 * time.Second, expOK: true},
+		{name: "should not wait for HTTP date in the past", value: "Sun, 31 Jan 2021 11:00:00 GMT", exp: 0, expOK: true},
+		{name: "should not overflow on huge seconds", value: "99999999999999999", exp: math.MaxInt64, expOK: true},
+		{name: "should ignore negative seconds", value: "-1"},
+		{name: "should ignore garbage", value: "soon"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.value != "" {
+				h.Set("Retry-After", tc.value)
+			}
+
+			d, ok := parseRetryAfter(h, now)
+			if d != tc.exp || ok != tc.expOK {
+				t.Errorf("unexpected result: got %s/%t, exp %s/%t", d, ok, tc.exp, tc.expOK)
+			}
+		})
+	}
+}
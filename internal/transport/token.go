@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTokenRefreshSkew is how long before expiry a token is refreshed.
+const DefaultTokenRefreshSkew = 30 * time.Second
+
+// tokenManager holds the current token and refreshes it before it expires.
+// It is safe for concurrent use, and concurrent refreshes are deduplicated so
+// that only one request to the identity server is in flight at a time.
+type tokenManager struct {
+	fetch    func(context.Context) (*Token, error)
+	now      func() time.Time
+	current  *Token
+	inflight *tokenCall
+	lifetime time.Duration
+	skew     time.Duration
+	mu       sync.Mutex
+}
+
+// tokenCall represents an in-flight token refresh shared by all waiting callers.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+func newTokenManager(fetch func(context.Context) (*Token, error), skew time.Duration) *tokenManager {
+	if skew <= 0 {
+		skew = DefaultTokenRefreshSkew
+	}
+
+	return &tokenManager{
+		fetch: fetch,
+		now:   time.Now,
+		skew:  skew,
+	}
+}
+
+// token returns a valid access token, refreshing it if it is missing or about to expire.
+func (m *tokenManager) token(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	if m.valid() {
+		token := m.current.AccessToken
+		m.mu.Unlock()
+
+		return token, nil
+	}
+	m.mu.Unlock()
+
+	t, err := m.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.AccessToken, nil
+}
+
+// refresh fetches a new token, joining an in-flight refresh if there is one.
+func (m *tokenManager) refresh(ctx context.Context) (*Token, error) {
+	for {
+		m.mu.Lock()
+		call := m.inflight
+		leader := call == nil
+		if leader {
+			call = &tokenCall{done: make(chan struct{})}
+			m.inflight = call
+		}
+		m.mu.Unlock()
+
+		if leader {
+			m.run(ctx, call)
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, classifyNetworkError(ctx.Err())
+		}
+
+		// The leader's context may have been cancelled while ours is still alive, try again.
+		if call.err != nil && !leader && ctx.Err() == nil && isContextError(call.err) {
+			continue
+		}
+
+		return call.token, call.err
+	}
+}
+
+func (m *tokenManager) run(ctx context.Context, call *tokenCall) {
+	t, err := m.fetch(ctx)
+	if err == nil && (t == nil || t.AccessToken == "") {
+		t, err = nil, ErrMissingToken
+	}
+
+	m.mu.Lock()
+	if err == nil {
+		m.current = t
+		m.lifetime = t.Expiry.Sub(m.now())
+	}
+	m.inflight = nil
+	m.mu.Unlock()
+
+	call.token, call.err = t, err
+	close(call.done)
+}
+
+// invalidate discards the current token, so that the next request fetches a new one.
+// It is used when the API rejects a token the client still considers valid.
+func (m *tokenManager) invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.current = nil
+}
+
+// valid reports whether the current token can be used. The caller must hold m.mu.
+func (m *tokenManager) valid() bool {
+	skew := m.skew
+	if skew > m.lifetime/2 {
+		skew = m.lifetime / 2
+	}
+
+	return m.current.valid(m.now(), skew)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestAuthServer(t *testing.T, expiresIn int, delay time.Duration) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		time.Sleep(delay)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newTestClient(srv *httptest.Server, skew time.Duration) *Client {
+	return New(context.Background(), &Config{
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		AuthURL:          srv.URL,
+		TokenRefreshSkew: skew,
+	}, srv.Client())
+}
+
+func TestTokenConcurrentRefresh(t *testing.T) {
+	srv, calls := newTestAuthServer(t, 3600, 20*time.Millisecond)
+	c := newTestClient(srv, 0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 100)
+
+	for i := range tokens {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			token, err := c.getToken(ctx)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			tokens[i] = token
+		}(i)
+	}
+
+	wg.Wait()
+
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("unexpected number of token requests: got %d, exp 1", n)
+	}
+
+	for _, token := range tokens {
+		if token != "token-1" {
+			t.Errorf("unexpected token: got %s, exp token-1", token)
+		}
+	}
+}
+
+func TestTokenProactiveRefresh(t *testing.T) {
+	srv, calls := newTestAuthServer(t, 3600, 0)
+	c := newTestClient(srv, time.Minute)
+	ctx := context.Background()
+
+	now := time.Now()
+	c.tokens.now = func() time.Time { return now }
+
+	tests := []struct {
+		name     string
+		advance  time.Duration
+		expToken string
+		expCalls int32
+	}{
+		{name: "should fetch initial token", expToken: "token-1", expCalls: 1},
+		{name: "should reuse valid token", advance: 58 * time.Minute, expToken: "token-1", expCalls: 1},
+		{name: "should refresh within skew window", advance: 90 * time.Second, expToken: "token-2", expCalls: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now = now.Add(tc.advance)
+
+			token, err := c.getToken(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token != tc.expToken {
+				t.Errorf("unexpected token: got %s, exp %s", token, tc.expToken)
+			}
+
+			if n := atomic.LoadInt32(calls); n != tc.expCalls {
+				t.Errorf("unexpected number of token requests: got %d, exp %d", n, tc.expCalls)
+			}
+		})
+	}
+}
+
+func TestTokenRefreshCancelledLeader(t *testing.T) {
+	var calls int32
+
+	m := newTokenManager(func(ctx context.Context) (*Token, error) {
+		n := atomic.AddInt32(&calls, 1)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+			return &Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(time.Hour)}, nil
+		}
+	}, 0)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if _, err := m.token(leaderCtx); !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected leader error: %v", err)
+		}
+	}()
+
+	// Make sure the leader has started the refresh before the follower joins.
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		token, err := m.token(context.Background())
+		if err != nil {
+			t.Errorf("unexpected follower error: %v", err)
+		}
+
+		if token != "token-2" {
+			t.Errorf("unexpected follower token: got %s, exp token-2", token)
+		}
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,268 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMissingToken are returned when a token source returns an empty token.
+var ErrMissingToken = errors.New("token source returned an empty token")
+
+// Token is an access token for the API.
+type Token struct {
+	// AccessToken is the bearer token sent with every request.
+	AccessToken string `json:"access_token"`
+	// Expiry is when the token expires. A zero Expiry means the token does not expire.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// valid reports whether the token can be used at now, refreshing skew before it expires.
+func (t *Token) valid(now time.Time, skew time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	if t.Expiry.IsZero() {
+		return true
+	}
+
+	return now.Before(t.Expiry.Add(-skew))
+}
+
+// TokenSource supplies access tokens. The client keeps the token in memory and
+// only asks the source for a new one when the current token is about to expire.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// SkewTokenSource is a TokenSource that is told the refresh skew of the client, so that it does
+// not return a token the client considers about to expire. The client calls TokenWithSkew instead
+// of Token on sources implementing it. Sources wrapping another source should implement it and
+// pass the skew on.
+type SkewTokenSource interface {
+	TokenSource
+	TokenWithSkew(ctx context.Context, skew time.Duration) (*Token, error)
+}
+
+type clientCredentials struct {
+	http         *http.Client
+	clientID     string
+	clientSecret string
+	authURL      string
+}
+
+// NewClientCredentialsTokenSource returns a TokenSource fetching tokens from the identity
+// server at authURL with the client credentials grant. If httpClient is nil, http.DefaultClient will be used.
+func NewClientCredentialsTokenSource(clientID, clientSecret, authURL string, httpClient *http.Client) TokenSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &clientCredentials{
+		http:         httpClient,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		authURL:      authURL,
+	}
+}
+
+// Token fetches a new token from the identity server.
+func (s *clientCredentials) Token(ctx context.Context) (*Token, error) {
+	payload := []byte("grant_type=client_credentials")
+
+	req, err := http.NewRequest(http.MethodPost, s.authURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := s.http.Do(req)
+	if err != nil {
+		return nil, classifyNetworkError(err)
+	}
+
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, classifyNetworkError(err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		authErr := &AuthError{StatusCode: res.StatusCode}
+
+		// The body is not necessarily an OAuth error, e.g. during outages, so decoding errors are ignored.
+		_ = json.Unmarshal(data, authErr)
+
+		return nil, authErr
+	}
+
+	var a auth
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		AccessToken: a.AccessToken,
+		Expiry:      time.Now().Add(time.Second * time.Duration(a.ExpiresIn)),
+	}, nil
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+// StaticTokenSource returns a TokenSource that always returns the given access token.
+// The token is never refreshed, so requests fail once it expires.
+func StaticTokenSource(accessToken string) TokenSource {
+	return &staticTokenSource{token: &Token{AccessToken: accessToken}}
+}
+
+// Token returns the static token.
+func (s *staticTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+type fileTokenSource struct {
+	path string
+}
+
+// FileTokenSource returns a TokenSource reading the token from a file. The file holds either
+// the raw access token, or a JSON encoded Token as written by CachingTokenSource.
+// The file is read again when the token expires, so it can be updated by another process.
+func FileTokenSource(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+// Token reads the token from the file.
+func (s *fileTokenSource) Token(ctx context.Context) (*Token, error) {
+	return readTokenFile(s.path)
+}
+
+type cachingTokenSource struct {
+	src  TokenSource
+	now  func() time.Time
+	path string
+	mu   sync.Mutex
+}
+
+// CachingTokenSource returns a TokenSource persisting the tokens of src to a file, so that the token
+// can be reused between runs of short-lived programs. A new token is fetched from src when the
+// cached token is missing or about to expire. The file is created with permissions 0600.
+// Failing to write the file does not fail the request.
+func CachingTokenSource(src TokenSource, path string) TokenSource {
+	return &cachingTokenSource{
+		src:  src,
+		now:  time.Now,
+		path: path,
+	}
+}
+
+// Token returns the cached token if it is still valid, otherwise a new token from the wrapped source.
+func (s *cachingTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.TokenWithSkew(ctx, DefaultTokenRefreshSkew)
+}
+
+// TokenWithSkew returns the cached token if it is valid for longer than skew, otherwise a new
+// token from the wrapped source, which is passed the skew if it is a SkewTokenSource.
+func (s *cachingTokenSource) TokenWithSkew(ctx context.Context, skew time.Duration) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, err := readTokenFile(s.path); err == nil && t.valid(s.now(), skew) {
+		return t, nil
+	}
+
+	t, err := tokenWithSkew(ctx, s.src, skew)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = writeTokenFile(s.path, t)
+
+	return t, nil
+}
+
+// tokenWithSkew returns a token from src, passing the skew if src is a SkewTokenSource.
+func tokenWithSkew(ctx context.Context, src TokenSource, skew time.Duration) (*Token, error) {
+	if s, ok := src.(SkewTokenSource); ok {
+		return s.TokenWithSkew(ctx, skew)
+	}
+
+	return src.Token(ctx)
+}
+
+func readTokenFile(path string) (*Token, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+
+	if !bytes.HasPrefix(data, []byte("{")) {
+		if len(data) == 0 {
+			return nil, fmt.Errorf("%s: %w", path, ErrMissingToken)
+		}
+
+		return &Token{AccessToken: string(data)}, nil
+	}
+
+	var t Token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if strings.TrimSpace(t.AccessToken) == "" {
+		return nil, fmt.Errorf("%s: %w", path, ErrMissingToken)
+	}
+
+	return &t, nil
+}
+
+// writeTokenFile writes the token to a temporary file and renames it, so that readers never see a partial token.
+func writeTokenFile(path string, t *Token) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,241 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testTokenSource struct {
+	calls int32
+	token *Token
+	err   error
+}
+
+func (s *testTokenSource) Token(ctx context.Context) (*Token, error) {
+	atomic.AddInt32(&s.calls, 1)
+
+	return s.token, s.err
+}
+
+func TestFileTokenSource(t *testing.T) {
+	dir := t.TempDir()
+	expiry := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		content   string
+		exp       *Token
+		expErr    error
+		expAnyErr bool
+	}{
+		{
+			name:    "should read raw token",
+			content: "raw-token\n",
+			exp:     &Token{AccessToken: "raw-token"},
+		},
+		{
+			name:    "should read JSON token",
+			content: `{"access_token":"json-token","expiry":"2030-01-01T00:00:00Z"}`,
+			exp:     &Token{AccessToken: "json-token", Expiry: expiry},
+		},
+		{
+			name:    "should return error on empty file",
+			content: " \n",
+			expErr:  ErrMissingToken,
+		},
+		{
+			name:    "should return error on empty JSON token",
+			content: `{"access_token":""}`,
+			expErr:  ErrMissingToken,
+		},
+		{
+			name:      "should return error on invalid JSON",
+			content:   `{"access_token":`,
+			expAnyErr: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i)))
+			if err := ioutil.WriteFile(path, []byte(tc.content), 0600); err != nil {
+				t.Fatalf("error setting up test: %v", err)
+			}
+
+			token, err := FileTokenSource(path).Token(context.Background())
+
+			if tc.expErr != nil || tc.expAnyErr {
+				if err == nil || (tc.expErr != nil && !errors.Is(err, tc.expErr)) {
+					t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token.AccessToken != tc.exp.AccessToken || !token.Expiry.Equal(tc.exp.Expiry) {
+				t.Errorf("unexpected token: got %+v, exp %+v", token, tc.exp)
+			}
+		})
+	}
+
+	t.Run("should return error on missing file", func(t *testing.T) {
+		_, err := FileTokenSource(filepath.Join(dir, "missing")).Token(context.Background())
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("unexpected error: got %v, exp %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+// testSkewTokenSource wraps a SkewTokenSource, passing the skew on.
+type testSkewTokenSource struct {
+	src SkewTokenSource
+}
+
+func (s *testSkewTokenSource) Token(ctx context.Context) (*Token, error) {
+	return s.src.Token(ctx)
+}
+
+func (s *testSkewTokenSource) TokenWithSkew(ctx context.Context, skew time.Duration) (*Token, error) {
+	return s.src.TokenWithSkew(ctx, skew)
+}
+
+func TestCachingTokenSource(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token.json")
+	src := &testTokenSource{token: &Token{AccessToken: "token-1", Expiry: time.Now().Add(time.Hour)}}
+
+	t.Run("should fetch and persist token", func(t *testing.T) {
+		token, err := CachingTokenSource(src, path).Token(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if token.AccessToken != "token-1" || atomic.LoadInt32(&src.calls) != 1 {
+			t.Errorf("unexpected token: got %+v after %d calls", token, src.calls)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected token file: %v", err)
+		}
+
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("unexpected permissions: got %o, exp 600", perm)
+		}
+	})
+
+	t.Run("should reuse persisted token between instances", func(t *testing.T) {
+		token, err := CachingTokenSource(src, path).Token(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if token.AccessToken != "token-1" || atomic.LoadInt32(&src.calls) != 1 {
+			t.Errorf("unexpected token: got %+v after %d calls", token, src.calls)
+		}
+	})
+
+	t.Run("should refresh expiring token", func(t *testing.T) {
+		src.token = &Token{AccessToken: "token-2", Expiry: time.Now().Add(time.Hour)}
+
+		s := CachingTokenSource(src, path).(*cachingTokenSource)
+		s.now = func() time.Time { return time.Now().Add(59*time.Minute + 31*time.Second) }
+
+		token, err := s.Token(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if token.AccessToken != "token-2" || atomic.LoadInt32(&src.calls) != 2 {
+			t.Errorf("unexpected token: got %+v after %d calls", token, src.calls)
+		}
+	})
+
+	t.Run("should refresh with configured skew", func(t *testing.T) {
+		tests := []struct {
+			name string
+			wrap func(TokenSource) TokenSource
+		}{
+			{name: "when used directly", wrap: func(src TokenSource) TokenSource { return src }},
+			{name: "when wrapped", wrap: func(src TokenSource) TokenSource { return &testSkewTokenSource{src: src.(SkewTokenSource)} }},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "token.json")
+				if err := writeTokenFile(path, &Token{AccessToken: "cached", Expiry: time.Now().Add(4 * time.Minute)}); err != nil {
+					t.Fatalf("error setting up test: %v", err)
+				}
+
+				src := &testTokenSource{token: &Token{AccessToken: "token-3", Expiry: time.Now().Add(time.Hour)}}
+				c := New(ctx, &Config{TokenSource: tc.wrap(CachingTokenSource(src, path)), TokenRefreshSkew: 5 * time.Minute}, nil)
+
+				for i := 0; i < 2; i++ {
+					token, err := c.tokens.token(ctx)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					if token != "token-3" || atomic.LoadInt32(&src.calls) != 1 {
+						t.Errorf("unexpected token: got %q after %d calls", token, src.calls)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("should return source errors", func(t *testing.T) {
+		errSource := errors.New("source failed")
+		s := CachingTokenSource(&testTokenSource{err: errSource}, filepath.Join(t.TempDir(), "token.json"))
+
+		if _, err := s.Token(ctx); !errors.Is(err, errSource) {
+			t.Errorf("unexpected error: got %v, exp %v", err, errSource)
+		}
+	})
+}
+
+func TestTokenManagerSources(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should never refresh token without expiry", func(t *testing.T) {
+		src := &testTokenSource{token: &Token{AccessToken: "static"}}
+		m := newTokenManager(src.Token, 0)
+
+		for i := 0; i < 3; i++ {
+			token, err := m.token(ctx)
+			if err != nil || token != "static" {
+				t.Fatalf("unexpected result: got %q, %v", token, err)
+			}
+		}
+
+		if n := atomic.LoadInt32(&src.calls); n != 1 {
+			t.Errorf("unexpected number of calls: got %d, exp 1", n)
+		}
+	})
+
+	t.Run("should return error on empty token", func(t *testing.T) {
+		m := newTokenManager((&testTokenSource{token: &Token{}}).Token, 0)
+
+		if _, err := m.token(ctx); !errors.Is(err, ErrMissingToken) {
+			t.Errorf("unexpected error: got %v, exp %v", err, ErrMissingToken)
+		}
+	})
+
+	t.Run("should use static token source", func(t *testing.T) {
+		m := newTokenManager(StaticTokenSource("static").Token, 0)
+
+		if token, err := m.token(ctx); err != nil || token != "static" {
+			t.Errorf("unexpected result: got %q, %v", token, err)
+		}
+	})
+}
@@ -3,15 +3,24 @@ package transport
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
+// Logger is the interface used for logging, satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
 // Client represents the transport client.
 type Client struct {
-	clientID     string
-	clientSecret string
-	userAgent    string
-	http         *http.Client
-	auth         *auth
+	userAgent   string
+	http        *http.Client
+	tokens      *tokenManager
+	retry       *RetryPolicy
+	limiter     *RateLimiter
+	handler     Handler
+	logger      Logger
+	maxBodySize int64
 }
 
 // Config represents the transport config.
@@ -19,18 +28,49 @@ type Config struct {
 	ClientID     string
 	ClientSecret string
 	UserAgent    string
+	AuthURL      string
+	// TokenSource supplies access tokens. Defaults to the client credentials grant with ClientID and ClientSecret.
+	TokenSource TokenSource
+	// TokenRefreshSkew is how long before expiry the token is refreshed. Defaults to DefaultTokenRefreshSkew.
+	TokenRefreshSkew time.Duration
+	// RetryPolicy configures retries of failed requests. Requests are not retried if nil.
+	RetryPolicy *RetryPolicy
+	// RateLimiter limits the rate of requests to the API. Requests are not limited if nil.
+	RateLimiter *RateLimiter
+	// MaxBodySize is the maximum size of a response body in bytes. Defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+	// Middleware wraps every attempt of a request, with the first middleware being the outermost.
+	Middleware []Middleware
+	// Logger is for optionally logging retried requests.
+	Logger Logger
 }
 
 // New returns a transport client.
 func New(ctx context.Context, cfg *Config, httpClient *http.Client) *Client {
 	c := &Client{
-		clientID:     cfg.ClientID,
-		clientSecret: cfg.ClientSecret,
-		userAgent:    cfg.UserAgent,
+		userAgent:   cfg.UserAgent,
+		retry:       cfg.RetryPolicy,
+		limiter:     cfg.RateLimiter,
+		logger:      cfg.Logger,
+		maxBodySize: cfg.MaxBodySize,
+	}
+
+	if c.maxBodySize <= 0 {
+		c.maxBodySize = DefaultMaxBodySize
 	}
 
 	c.setHTTPClient(httpClient)
 
+	src := cfg.TokenSource
+	if src == nil {
+		src = NewClientCredentialsTokenSource(cfg.ClientID, cfg.ClientSecret, cfg.AuthURL, c.http)
+	}
+
+	c.tokens = newTokenManager(func(ctx context.Context) (*Token, error) {
+		return tokenWithSkew(ctx, src, c.tokens.skew)
+	}, cfg.TokenRefreshSkew)
+	c.handler = chain(c.send, cfg.Middleware)
+
 	return c
 }
 
@@ -0,0 +1,185 @@
+package sbanken
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items iterators request per page when no page size is set.
+const DefaultPageSize = 100
+
+// PageOptions configures how iterators page through list endpoints.
+type PageOptions struct {
+	// PageSize is the number of items requested per page. Defaults to the Length
+	// of the query if set, or DefaultPageSize.
+	PageSize int
+	// MaxItems caps the total number of items returned. Zero means no cap.
+	MaxItems int
+}
+
+// pager keeps track of index and length while iterating over a list endpoint.
+// The iteration starts at the Index of the query and stops when AvailableItems
+// are fetched, when a page comes back empty, or when MaxItems are reached.
+type pager struct {
+	err       error
+	index     int
+	pageSize  int
+	maxItems  int
+	fetched   int
+	length    int
+	available int
+	done      bool
+}
+
+func newPager(index, length string, opts *PageOptions) pager {
+	p := pager{pageSize: DefaultPageSize, available: -1}
+
+	if index != "" {
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 {
+			p.err = fmt.Errorf("invalid Index: %q", index)
+			return p
+		}
+
+		p.index = i
+	}
+
+	if length != "" {
+		l, err := strconv.Atoi(length)
+		if err != nil || l <= 0 {
+			p.err = fmt.Errorf("invalid Length: %q", length)
+			return p
+		}
+
+		p.pageSize = l
+	}
+
+	if opts != nil {
+		if opts.PageSize > 0 {
+			p.pageSize = opts.PageSize
+		}
+
+		p.maxItems = opts.MaxItems
+	}
+
+	return p
+}
+
+// nextPage returns the index and length of the next page to fetch, or false if there are no more pages.
+func (p *pager) nextPage() (string, string, bool) {
+	if p.err != nil || p.done {
+		return "", "", false
+	}
+
+	if p.available >= 0 && p.index >= p.available {
+		return "", "", false
+	}
+
+	length := p.pageSize
+	if p.maxItems > 0 {
+		remaining := p.maxItems - p.fetched
+		if remaining <= 0 {
+			return "", "", false
+		}
+
+		if remaining < length {
+			length = remaining
+		}
+	}
+
+	p.length = length
+
+	return strconv.Itoa(p.index), strconv.Itoa(length), true
+}
+
+// clamp returns how many of n fetched items can be used without exceeding MaxItems.
+func (p *pager) clamp(n int) int {
+	if p.maxItems > 0 && p.fetched+n > p.maxItems {
+		return p.maxItems - p.fetched
+	}
+
+	return n
+}
+
+// advance records that a page of n items was fetched, out of available items in total.
+func (p *pager) advance(n, available int) {
+	p.index += n
+	p.fetched += n
+
+	if available > 0 {
+		p.available = available
+	} else if n < p.length {
+		p.done = true
+	}
+
+	if n == 0 {
+		p.done = true
+	}
+}
+
+// Iterator iterates over the items of a list endpoint across pages, see TransactionIterator,
+// PaymentIterator and EfakturaIterator.
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, index, length string) ([]T, int, error)
+	buf   []T
+	cur   T
+	pager pager
+}
+
+// newIterator returns an iterator starting at the index and length of a query.
+// fetch returns the items of a page and the number of available items.
+func newIterator[T any](index, length string, opts *PageOptions, fetch func(ctx context.Context, index, length string) ([]T, int, error)) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		pager: newPager(index, length, opts),
+	}
+}
+
+// Next advances the iterator to the next item, fetching a new page when needed.
+// It returns false when there are no more items or an error occurred.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.buf) == 0 {
+		index, length, ok := it.pager.nextPage()
+		if !ok {
+			return false
+		}
+
+		items, available, err := it.fetch(ctx, index, length)
+		if err != nil {
+			it.pager.err = err
+			return false
+		}
+
+		it.buf = items[:it.pager.clamp(len(items))]
+		it.pager.advance(len(it.buf), available)
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+
+	return true
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T {
+	return it.cur
+}
+
+// AvailableItems returns the total number of available items, or -1 before the first page is fetched.
+func (it *Iterator[T]) AvailableItems() int {
+	return it.pager.available
+}
+
+// Err returns the first error encountered by the iterator.
+func (it *Iterator[T]) Err() error {
+	return it.pager.err
+}
+
+func (it *Iterator[T]) all(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+
+	return items, it.Err()
+}
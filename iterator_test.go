@@ -0,0 +1,254 @@
+package sbanken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+const testTraceID = "0HM5B1ERQ6TF4:00000001"
+
+// testPagingTransportClient serves total generated items from any list endpoint, honouring index and length.
+type testPagingTransportClient struct {
+	requests *[]string
+	total    int
+	failAt   int
+}
+
+func (c testPagingTransportClient) Authorize(ctx context.Context) error {
+	return nil
+}
+
+func (c testPagingTransportClient) Request(ctx context.Context, r *transport.HTTPRequest) ([]byte, int, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	*c.requests = append(*c.requests, u.RawQuery)
+
+	index, _ := strconv.Atoi(u.Query().Get("index"))
+	length, _ := strconv.Atoi(u.Query().Get("length"))
+
+	if c.failAt > 0 && index >= c.failAt {
+		res := testHTTPResponseError
+		res.TraceID = testTraceID
+
+		b, err := json.Marshal(res)
+		return b, http.StatusInternalServerError, err
+	}
+
+	var items []map[string]string
+	for i := index; i < index+length && i < c.total; i++ {
+		id := fmt.Sprintf("item-%d", i)
+		items = append(items, map[string]string{
+			"transactionId": id,
+			"paymentId":     id,
+			"eFakturaId":    id,
+		})
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"availableItems": c.total,
+		"traceId":        testTraceID,
+		"items":          items,
+	})
+
+	return b, http.StatusOK, err
+}
+
+func newTestPagingClient(ctx context.Context, t *testing.T, total, failAt int) (*Client, *[]string) {
+	t.Helper()
+
+	c, err := newTestClient(ctx, t)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	var requests []string
+	c.transport = testPagingTransportClient{requests: &requests, total: total, failAt: failAt}
+
+	return c, &requests
+}
+
+func TestTransactionIterator(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		total       int
+		failAt      int
+		q           *TransactionListQuery
+		opts        *PageOptions
+		expIDs      int
+		expFirst    string
+		expRequests []string
+		expErr      bool
+	}{
+		{
+			name:        "should fetch all pages",
+			total:       250,
+			expIDs:      250,
+			expFirst:    "item-0",
+			expRequests: []string{"index=0&length=100", "index=100&length=100", "index=200&length=100"},
+		},
+		{
+			name:        "should use page size",
+			total:       5,
+			opts:        &PageOptions{PageSize: 2},
+			expIDs:      5,
+			expFirst:    "item-0",
+			expRequests: []string{"index=0&length=2", "index=2&length=2", "index=4&length=2"},
+		},
+		{
+			name:        "should use query index and length",
+			total:       10,
+			q:           &TransactionListQuery{Index: "4", Length: "3"},
+			expIDs:      6,
+			expFirst:    "item-4",
+			expRequests: []string{"index=4&length=3", "index=7&length=3"},
+		},
+		{
+			name:        "should stop at max items",
+			total:       250,
+			opts:        &PageOptions{PageSize: 100, MaxItems: 150},
+			expIDs:      150,
+			expFirst:    "item-0",
+			expRequests: []string{"index=0&length=100", "index=100&length=50"},
+		},
+		{
+			name:        "should handle empty list",
+			total:       0,
+			expRequests: []string{"index=0&length=100"},
+		},
+		{
+			name:        "should stop on error",
+			total:       250,
+			failAt:      100,
+			expIDs:      100,
+			expFirst:    "item-0",
+			expRequests: []string{"index=0&length=100", "index=100&length=100"},
+			expErr:      true,
+		},
+		{
+			name:   "should fail on invalid index",
+			q:      &TransactionListQuery{Index: "first"},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, requests := newTestPagingClient(ctx, t, tc.total, tc.failAt)
+
+			it := c.IterateTransactions("test-account", tc.q, tc.opts)
+
+			var ids []string
+			for it.Next(ctx) {
+				ids = append(ids, it.Transaction().TransactionID)
+			}
+
+			if (it.Err() != nil) != tc.expErr {
+				t.Fatalf("unexpected error: %v", it.Err())
+			}
+
+			if len(ids) != tc.expIDs {
+				t.Errorf("unexpected number of transactions: got %d, exp %d", len(ids), tc.expIDs)
+			}
+
+			if len(ids) > 0 && ids[0] != tc.expFirst {
+				t.Errorf("unexpected first transaction: got %s, exp %s", ids[0], tc.expFirst)
+			}
+
+			if !reflect.DeepEqual(*requests, tc.expRequests) && !(len(*requests) == 0 && tc.expRequests == nil) {
+				t.Errorf("unexpected requests: got %v, exp %v", *requests, tc.expRequests)
+			}
+		})
+	}
+
+	t.Run("should not modify the query", func(t *testing.T) {
+		c, _ := newTestPagingClient(ctx, t, 10, 0)
+		q := &TransactionListQuery{Length: "3"}
+
+		if _, err := c.ListAllArchivedTransactions(ctx, "test-account", q, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if q.Index != "" || q.Length != "3" {
+			t.Errorf("unexpected query: %+v", q)
+		}
+	})
+
+	t.Run("should fail when no accountID is passed", func(t *testing.T) {
+		c, _ := newTestPagingClient(ctx, t, 10, 0)
+
+		if _, err := c.ListAllTransactions(ctx, "", nil, nil); !errors.Is(err, ErrMissingAccountID) {
+			t.Errorf("unexpected error: got %v, exp %v", err, ErrMissingAccountID)
+		}
+	})
+}
+
+func TestListAllPayments(t *testing.T) {
+	ctx := context.Background()
+	c, requests := newTestPagingClient(ctx, t, 7, 0)
+
+	payments, err := c.ListAllPayments(ctx, "test-account", nil, &PageOptions{PageSize: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(payments) != 7 || payments[6].ID != "item-6" {
+		t.Errorf("unexpected payments: %v", payments)
+	}
+
+	if len(*requests) != 3 {
+		t.Errorf("unexpected number of requests: got %d, exp 3", len(*requests))
+	}
+}
+
+func TestListAllEfakturas(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should list all efakturas", func(t *testing.T) {
+		c, requests := newTestPagingClient(ctx, t, 4, 0)
+
+		it := c.IterateEfakturas(&EfakturaListQuery{Status: "ALL"}, &PageOptions{PageSize: 2})
+
+		var n int
+		for it.Next(ctx) {
+			n++
+		}
+
+		if it.Err() != nil {
+			t.Fatalf("unexpected error: %v", it.Err())
+		}
+
+		if n != 4 || it.AvailableItems() != 4 {
+			t.Errorf("unexpected efakturas: got %d of %d", n, it.AvailableItems())
+		}
+
+		for _, r := range *requests {
+			if !strings.Contains(r, "status=ALL") {
+				t.Errorf("expected status in query: %s", r)
+			}
+		}
+	})
+
+	t.Run("should fail with invalid query for new efakturas", func(t *testing.T) {
+		c, _ := newTestPagingClient(ctx, t, 4, 0)
+
+		_, err := c.ListAllNewEfakturas(ctx, &EfakturaListQuery{Status: "ALL"}, nil)
+		if !errors.Is(err, ErrNotValidOptionStatus) {
+			t.Errorf("unexpected error: got %v, exp %v", err, ErrNotValidOptionStatus)
+		}
+	})
+}
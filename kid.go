@@ -0,0 +1,170 @@
+package sbanken
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidKID are returned when a KID is not 2 to 25 digits, or a KID base can not get a check digit.
+	ErrInvalidKID = errors.New("invalid KID")
+	// ErrKIDCheckDigit are returned when the check digit of a KID does not match.
+	ErrKIDCheckDigit = errors.New("invalid KID check digit")
+)
+
+const (
+	minKIDLength = 2
+	maxKIDLength = 25
+)
+
+// KIDAlgorithm is the check digit algorithm of a KID, agreed between the issuer and its bank.
+type KIDAlgorithm int
+
+const (
+	// KIDMOD10 is the MOD10 (Luhn) algorithm.
+	KIDMOD10 KIDAlgorithm = iota + 1
+	// KIDMOD11 is the MOD11 algorithm. A check digit of 10 is written as "-".
+	KIDMOD11
+)
+
+// String returns the name of the algorithm.
+func (alg KIDAlgorithm) String() string {
+	switch alg {
+	case KIDMOD10:
+		return "MOD10"
+	case KIDMOD11:
+		return "MOD11"
+	}
+
+	return fmt.Sprintf("KIDAlgorithm(%d)", int(alg))
+}
+
+// KID represents a Norwegian customer identification number (kundeidentifikasjon), the
+// reference identifying the payer of an invoice. It is 2 to 25 digits, where the last digit
+// is a MOD10 or MOD11 check digit. MOD11 KIDs may end with "-".
+//
+// KIDs from the API are kept as they are. Use Valid to check a value before paying it.
+type KID string
+
+// ParseKID parses a KID, ignoring spaces, and validates its check digit with either algorithm.
+func ParseKID(s string) (KID, error) {
+	k := KID(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+
+	if err := k.check(); err != nil {
+		return "", fmt.Errorf("%w: %q", err, s)
+	}
+
+	if !k.validFor(KIDMOD10) && !k.validFor(KIDMOD11) {
+		return "", fmt.Errorf("%w: %q", ErrKIDCheckDigit, s)
+	}
+
+	return k, nil
+}
+
+// GenerateKID returns a KID for an invoice by appending the check digit of the algorithm to base.
+// The base must be 1 to 24 digits. MOD11 KIDs get "-" as check digit when the remainder is 1,
+// which some issuers avoid by choosing another base.
+func GenerateKID(base string, alg KIDAlgorithm) (KID, error) {
+	if len(base) < minKIDLength-1 || len(base) > maxKIDLength-1 || !isDigits(base) {
+		return "", fmt.Errorf("%w: base %q", ErrInvalidKID, base)
+	}
+
+	switch alg {
+	case KIDMOD10:
+		return KID(base + string(luhn(base))), nil
+	case KIDMOD11:
+		return KID(base + string(kidMOD11(base))), nil
+	}
+
+	return "", fmt.Errorf("%w: unknown algorithm %s", ErrInvalidKID, alg)
+}
+
+// Valid reports whether the KID has a valid MOD10 or MOD11 check digit.
+func (k KID) Valid() bool {
+	_, err := ParseKID(string(k))
+	return err == nil
+}
+
+// ValidFor reports whether the KID has a valid check digit for the algorithm.
+func (k KID) ValidFor(alg KIDAlgorithm) bool {
+	parsed, err := ParseKID(string(k))
+	return err == nil && parsed.validFor(alg)
+}
+
+// check returns an error if the KID does not have the length and characters of a KID.
+func (k KID) check() error {
+	if len(k) < minKIDLength || len(k) > maxKIDLength {
+		return ErrInvalidKID
+	}
+
+	base, check := string(k[:len(k)-1]), k[len(k)-1]
+	if !isDigits(base) || !(isDigit(check) || check == '-') {
+		return ErrInvalidKID
+	}
+
+	return nil
+}
+
+func (k KID) validFor(alg KIDAlgorithm) bool {
+	base, check := string(k[:len(k)-1]), k[len(k)-1]
+
+	switch alg {
+	case KIDMOD10:
+		return luhn(base) == check
+	case KIDMOD11:
+		return kidMOD11(base) == check
+	}
+
+	return false
+}
+
+// luhn returns the MOD10 check digit of the digits, doubling every second digit from the right.
+func luhn(digits string) byte {
+	sum := 0
+
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+// kidMOD11 returns the MOD11 check digit of the digits, with the weights 2 to 7 repeating from the right.
+// A check digit of 10 is returned as '-'.
+func kidMOD11(digits string) byte {
+	weights := make([]int, len(digits))
+	for i := range weights {
+		weights[len(digits)-1-i] = 2 + i%6
+	}
+
+	check, ok := mod11(digits, weights)
+	if !ok {
+		return '-'
+	}
+
+	return byte('0' + check)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,109 @@
+package sbanken
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseKID(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		exp    KID
+		expErr error
+	}{
+		{name: "should parse MOD10 KID", in: "79927398713", exp: "79927398713"},
+		{name: "should parse MOD11 KID", in: "123456785", exp: "123456785"},
+		{name: "should parse MOD11 KID with dash", in: "104-", exp: "104-"},
+		{name: "should parse KID with spaces", in: " 7992 7398 713 ", exp: "79927398713"},
+		{name: "should parse shortest KID", in: "00", exp: "00"},
+		{name: "should fail on wrong check digit", in: "79927398710", expErr: ErrKIDCheckDigit},
+		{name: "should fail on dash with wrong base", in: "105-", expErr: ErrKIDCheckDigit},
+		{name: "should fail on too short KID", in: "0", expErr: ErrInvalidKID},
+		{name: "should fail on too long KID", in: "12345678901234567890123456", expErr: ErrInvalidKID},
+		{name: "should fail on letters", in: "12A4", expErr: ErrInvalidKID},
+		{name: "should fail on dash before check digit", in: "1-4", expErr: ErrInvalidKID},
+		{name: "should fail on empty string", in: "", expErr: ErrInvalidKID},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := ParseKID(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if k != tc.exp {
+				t.Errorf("unexpected KID: got %q, exp %q", k, tc.exp)
+			}
+		})
+	}
+}
+
+func TestKIDValidFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        KID
+		expMOD10 bool
+		expMOD11 bool
+	}{
+		{name: "should be valid MOD10 only", k: "79927398713", expMOD10: true},
+		{name: "should be valid MOD11 only", k: "123456785", expMOD11: true},
+		{name: "should be valid MOD11 with dash", k: "104-", expMOD11: true},
+		{name: "should be valid for both", k: "12345678903", expMOD10: true, expMOD11: true},
+		{name: "should be invalid", k: "12345678904"},
+		{name: "should be invalid when empty"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.k.ValidFor(KIDMOD10); got != tc.expMOD10 {
+				t.Errorf("unexpected MOD10 validity: got %t, exp %t", got, tc.expMOD10)
+			}
+
+			if got := tc.k.ValidFor(KIDMOD11); got != tc.expMOD11 {
+				t.Errorf("unexpected MOD11 validity: got %t, exp %t", got, tc.expMOD11)
+			}
+
+			if got := tc.k.Valid(); got != (tc.expMOD10 || tc.expMOD11) {
+				t.Errorf("unexpected validity: got %t", got)
+			}
+		})
+	}
+}
+
+func TestGenerateKID(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   string
+		alg    KIDAlgorithm
+		exp    KID
+		expErr error
+	}{
+		{name: "should generate MOD10 KID", base: "7992739871", alg: KIDMOD10, exp: "79927398713"},
+		{name: "should generate MOD11 KID", base: "12345678", alg: KIDMOD11, exp: "123456785"},
+		{name: "should generate MOD11 KID with dash", base: "104", alg: KIDMOD11, exp: "104-"},
+		{name: "should generate KID from single digit", base: "0", alg: KIDMOD10, exp: "00"},
+		{name: "should fail on empty base", alg: KIDMOD10, expErr: ErrInvalidKID},
+		{name: "should fail on too long base", base: "1234567890123456789012345", alg: KIDMOD10, expErr: ErrInvalidKID},
+		{name: "should fail on letters", base: "12A", alg: KIDMOD11, expErr: ErrInvalidKID},
+		{name: "should fail on unknown algorithm", base: "123", expErr: ErrInvalidKID},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := GenerateKID(tc.base, tc.alg)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if k != tc.exp {
+				t.Errorf("unexpected KID: got %q, exp %q", k, tc.exp)
+			}
+
+			if tc.expErr == nil && !k.ValidFor(tc.alg) {
+				t.Errorf("generated KID not valid for %s: %q", tc.alg, k)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package sbanken
+
+import "github.com/engvik/sbanken-go/internal/transport"
+
+// Middleware wraps every attempt of a request to the API, in the spirit of chaining
+// http.RoundTripper. Register it through Config.Middleware. A middleware can inspect
+// and modify the request, such as its Header, and inspect the response, or return
+// a response or error without calling the next handler:
+//
+//	logger := func(next sbanken.Handler) sbanken.Handler {
+//		return func(ctx context.Context, r *sbanken.HTTPRequest) (*sbanken.Response, error) {
+//			res, err := next(ctx, r)
+//			if res != nil {
+//				log.Printf("%s: %d in %s", r.Operation, res.StatusCode, res.Latency)
+//			}
+//			return res, err
+//		}
+//	}
+//
+// Middleware runs inside the retry loop, so it is called once for every attempt.
+type Middleware = transport.Middleware
+
+// Handler performs a single attempt of a request.
+type Handler = transport.Handler
+
+// HTTPRequest represents a request to the API, as seen by Middleware.
+// Operation is the name of the client method, such as "ListAccounts" or "Transfer".
+type HTTPRequest = transport.HTTPRequest
+
+// Response represents the response of a single attempt of a request, as seen by Middleware.
+type Response = transport.Response
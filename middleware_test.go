@@ -0,0 +1,73 @@
+package sbanken
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "some-client-id", "some-client-secret")
+
+	var (
+		operation  string
+		statusCode int
+		body       []byte
+	)
+
+	audit := func(next Handler) Handler {
+		return func(ctx context.Context, r *HTTPRequest) (*Response, error) {
+			operation = r.Operation
+
+			res, err := next(ctx, r)
+			if res != nil {
+				statusCode = res.StatusCode
+				body = res.Body
+			}
+
+			return res, err
+		}
+	}
+
+	c, err := NewClient(ctx, &Config{
+		ClientID:     "some-client-id",
+		ClientSecret: "some-client-secret",
+		BaseURL:      srv.URL + "/api",
+		AuthURL:      srv.URL + "/connect/token",
+		APIVersion:   "v2",
+		Middleware:   []Middleware{audit},
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.ListAccounts(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("should see operation name", func(t *testing.T) {
+		if operation != "ListAccounts" {
+			t.Errorf("unexpected operation: got %q, exp %q", operation, "ListAccounts")
+		}
+	})
+
+	t.Run("should see status code and raw body", func(t *testing.T) {
+		if statusCode != http.StatusOK {
+			t.Errorf("unexpected status code: got %d, exp %d", statusCode, http.StatusOK)
+		}
+
+		if len(body) == 0 {
+			t.Error("expected raw body")
+		}
+	})
+
+	t.Run("should see operation name of archived transactions", func(t *testing.T) {
+		// The test server has no transactions endpoint, so only the operation seen by the middleware is checked.
+		_, _ = c.ListArchivedTransactions(ctx, "test-account", nil)
+
+		if operation != "ListArchivedTransactions" {
+			t.Errorf("unexpected operation: got %q, exp %q", operation, "ListArchivedTransactions")
+		}
+	})
+}
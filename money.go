@@ -0,0 +1,294 @@
+package sbanken
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidMoney are returned when an amount can not be parsed.
+	ErrInvalidMoney = errors.New("invalid amount")
+	// ErrMoneyPrecision are returned when an amount has more precision than øre.
+	ErrMoneyPrecision = errors.New("amount has more precision than øre")
+	// ErrMoneyOverflow are returned when an amount does not fit in Money.
+	ErrMoneyOverflow = errors.New("amount out of range")
+)
+
+// Money represents an amount in NOK with fixed-point øre precision.
+// The underlying value is the amount in øre, so Money(12345) is 123.45 NOK.
+//
+// Money marshals to and from JSON numbers without going through floating point,
+// so the decimal values returned by the API round-trip exactly.
+type Money int64
+
+// ParseMoney parses a decimal string such as "1337", "-12.5" or "123.45" into Money.
+// Exponents are accepted, but any non-zero digit beyond øre results in ErrMoneyPrecision.
+func ParseMoney(s string) (Money, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	neg := false
+	switch str[0] {
+	case '-':
+		neg = true
+		str = str[1:]
+	case '+':
+		str = str[1:]
+	}
+
+	exp := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+		}
+
+		exp = e
+		str = str[:i]
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	digits := intPart + fracPart
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+		}
+	}
+
+	// The value is digits * 10^(exp - len(fracPart)), and we want it in øre (10^-2).
+	shift := exp - len(fracPart) + 2
+	digits = strings.TrimLeft(digits, "0")
+
+	if shift < 0 {
+		cut := len(digits) + shift
+		if cut < 0 {
+			cut = 0
+		}
+
+		if strings.Trim(digits[cut:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q", ErrMoneyPrecision, s)
+		}
+
+		digits = digits[:cut]
+	} else if digits != "" {
+		if len(digits)+shift > 19 {
+			return 0, fmt.Errorf("%w: %q", ErrMoneyOverflow, s)
+		}
+
+		digits += strings.Repeat("0", shift)
+	}
+
+	if digits == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrMoneyOverflow, s)
+	}
+
+	if neg {
+		v = -v
+	}
+
+	return Money(v), nil
+}
+
+// MoneyFromFloat converts a floating point amount in NOK to Money, rounding half away from zero
+// to the nearest øre. It is intended for migrating code that used the previous float32 amounts.
+// Amounts that do not fit in Money return ErrMoneyOverflow, and NaN and infinities ErrInvalidMoney.
+func MoneyFromFloat(f float64) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidMoney, f)
+	}
+
+	// The shortest decimal representation is rounded rather than f * 100, which is off for
+	// amounts such as 0.285 that are slightly below the decimal value in binary.
+	s := strconv.FormatFloat(math.Abs(f), 'f', -1, 64)
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	roundUp := false
+	if len(fracPart) > 2 {
+		roundUp = fracPart[2] >= '5'
+		fracPart = fracPart[:2]
+	}
+
+	m, err := ParseMoney(intPart + "." + fracPart)
+	if err != nil {
+		return 0, err
+	}
+
+	if roundUp {
+		if m == math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %v", ErrMoneyOverflow, f)
+		}
+
+		m++
+	}
+
+	if f < 0 {
+		m = -m
+	}
+
+	return m, nil
+}
+
+// Float64 returns the amount in NOK as a float64. The result may not be exact.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Kroner returns the whole kroner part of the amount, truncated towards zero.
+func (m Money) Kroner() int64 {
+	return int64(m) / 100
+}
+
+// Ore returns the øre part of the amount, always in the range 0-99.
+func (m Money) Ore() int64 {
+	o := int64(m) % 100
+	if o < 0 {
+		o = -o
+	}
+
+	return o
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul returns m multiplied by n.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return -m
+}
+
+// Abs returns the absolute value of m.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+
+	return m
+}
+
+// Cmp compares m and o and returns -1 if m < o, 0 if m == o and +1 if m > o.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m < o:
+		return -1
+	case m > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// IsNegative reports whether the amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+// SumMoney returns the sum of the given amounts.
+func SumMoney(amounts ...Money) Money {
+	var sum Money
+	for _, a := range amounts {
+		sum += a
+	}
+
+	return sum
+}
+
+// String returns the amount with two decimals and a dot as decimal separator, e.g. "-1234.50".
+func (m Money) String() string {
+	return m.Format("", ".")
+}
+
+// Format returns the amount with two decimals, using the given thousands and decimal separators.
+// Format(" ", ",") gives the Norwegian notation, e.g. "1 234,50".
+func (m Money) Format(thousandsSep, decimalSep string) string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+
+	kr := strings.TrimPrefix(strconv.FormatInt(m.Kroner(), 10), "-")
+
+	if thousandsSep != "" && len(kr) > 3 {
+		var b strings.Builder
+		pre := len(kr) % 3
+		if pre > 0 {
+			b.WriteString(kr[:pre])
+		}
+
+		for i := pre; i < len(kr); i += 3 {
+			if b.Len() > 0 {
+				b.WriteString(thousandsSep)
+			}
+
+			b.WriteString(kr[i : i+3])
+		}
+
+		kr = b.String()
+	}
+
+	return fmt.Sprintf("%s%s%s%02d", sign, kr, decimalSep, m.Ore())
+}
+
+// MarshalJSON implements json.Marshaler. The amount is encoded as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both JSON numbers and strings are accepted.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+
+	return nil
+}
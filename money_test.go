@@ -0,0 +1,179 @@
+package sbanken
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		exp    Money
+		expErr error
+	}{
+		{name: "should parse integer", in: "1337", exp: 133700},
+		{name: "should parse two decimals", in: "123.45", exp: 12345},
+		{name: "should parse one decimal", in: "-12.5", exp: -1250},
+		{name: "should parse trailing zeros", in: "10.500", exp: 1050},
+		{name: "should parse leading dot", in: ".05", exp: 5},
+		{name: "should parse exponent", in: "1.5e2", exp: 15000},
+		{name: "should parse negative exponent", in: "12345E-2", exp: 12345},
+		{name: "should parse large amount exactly", in: "16777217.01", exp: 1677721701},
+		{name: "should parse zero", in: "-0.00", exp: 0},
+		{name: "should fail on sub-øre precision", in: "1.001", expErr: ErrMoneyPrecision},
+		{name: "should fail on garbage", in: "12,50", expErr: ErrInvalidMoney},
+		{name: "should fail on empty string", in: "", expErr: ErrInvalidMoney},
+		{name: "should fail on overflow", in: "1e20", expErr: ErrMoneyOverflow},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMoney(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if m != tc.exp {
+				t.Errorf("unexpected amount: got %d, exp %d", m, tc.exp)
+			}
+		})
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         Money
+		thousands string
+		decimal   string
+		exp       string
+	}{
+		{name: "should format zero", m: 0, decimal: ".", exp: "0.00"},
+		{name: "should format øre", m: -5, decimal: ".", exp: "-0.05"},
+		{name: "should format without separators", m: 123456789, decimal: ".", exp: "1234567.89"},
+		{name: "should format norwegian notation", m: 123456789, thousands: " ", decimal: ",", exp: "1 234 567,89"},
+		{name: "should format negative with separators", m: -100000, thousands: " ", decimal: ",", exp: "-1 000,00"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if s := tc.m.Format(tc.thousands, tc.decimal); s != tc.exp {
+				t.Errorf("unexpected format: got %s, exp %s", s, tc.exp)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := Money(1050), Money(-275)
+
+	if got := a.Add(b); got != 775 {
+		t.Errorf("unexpected sum: got %s", got)
+	}
+
+	if got := a.Sub(b); got != 1325 {
+		t.Errorf("unexpected difference: got %s", got)
+	}
+
+	if got := b.Mul(3); got != -825 {
+		t.Errorf("unexpected product: got %s", got)
+	}
+
+	if got := b.Abs(); got != 275 {
+		t.Errorf("unexpected absolute value: got %s", got)
+	}
+
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 {
+		t.Errorf("unexpected comparison")
+	}
+
+	if got := SumMoney(a, b, 25); got != 800 {
+		t.Errorf("unexpected sum: got %s", got)
+	}
+
+	if got := b.Kroner(); got != -2 {
+		t.Errorf("unexpected kroner: got %d", got)
+	}
+
+	if got := b.Ore(); got != 75 {
+		t.Errorf("unexpected øre: got %d", got)
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     float64
+		exp    Money
+		expErr error
+	}{
+		{name: "should convert float", in: 1337.5, exp: 1337_50},
+		{name: "should round sum of floats", in: 0.1 + 0.2, exp: 30},
+		{name: "should round half away from zero", in: 0.285, exp: 29},
+		{name: "should round negative half away from zero", in: -0.285, exp: -29},
+		{name: "should round down", in: 1.234, exp: 123},
+		{name: "should convert large amount", in: 1e15, exp: 1e17},
+		{name: "should fail on overflow", in: 1e17, expErr: ErrMoneyOverflow},
+		{name: "should fail on negative overflow", in: -1e17, expErr: ErrMoneyOverflow},
+		{name: "should fail on NaN", in: math.NaN(), expErr: ErrInvalidMoney},
+		{name: "should fail on infinity", in: math.Inf(1), expErr: ErrInvalidMoney},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := MoneyFromFloat(tc.in)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if m != tc.exp {
+				t.Errorf("unexpected amount: got %s, exp %s", m, tc.exp)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{name: "should round-trip number", in: `{"amount":167772.17}`, exp: `{"amount":167772.17}`},
+		{name: "should round-trip negative number", in: `{"amount":-0.01}`, exp: `{"amount":-0.01}`},
+		{name: "should normalize decimals", in: `{"amount":42}`, exp: `{"amount":42.00}`},
+		{name: "should accept string", in: `{"amount":"99.90"}`, exp: `{"amount":99.90}`},
+		{name: "should accept null", in: `{"amount":null}`, exp: `{"amount":0.00}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var d struct {
+				Amount Money `json:"amount"`
+			}
+
+			if err := json.Unmarshal([]byte(tc.in), &d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(b) != tc.exp {
+				t.Errorf("unexpected json: got %s, exp %s", b, tc.exp)
+			}
+		})
+	}
+
+	t.Run("should fail on sub-øre precision", func(t *testing.T) {
+		var m Money
+		if err := json.Unmarshal([]byte("1.234"), &m); !errors.Is(err, ErrMoneyPrecision) {
+			t.Errorf("unexpected error: got %v, exp %v", err, ErrMoneyPrecision)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package sbanken
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Option configures a client created with NewClientWithOptions.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	cfg        Config
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewClientWithOptions returns a new Sbanken client configured by the given options.
+// Options are applied in order, so later options override earlier ones:
+//
+//	c, err := sbanken.NewClientWithOptions(ctx,
+//		sbanken.WithCredentials(clientID, clientSecret),
+//		sbanken.WithTimeout(10*time.Second),
+//		sbanken.WithRetryPolicy(&sbanken.RetryPolicy{MaxAttempts: 3}),
+//	)
+func NewClientWithOptions(ctx context.Context, opts ...Option) (*Client, error) {
+	o := &clientOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.timeout < 0 {
+		return nil, fmt.Errorf("validate: %w", ErrInvalidTimeout)
+	}
+
+	return newClient(ctx, &o.cfg, o.httpClient)
+}
+
+// WithConfig sets all options from cfg. Options after it override the corresponding fields.
+func WithConfig(cfg *Config) Option {
+	return func(o *clientOptions) {
+		if cfg != nil {
+			o.cfg = *cfg
+		}
+	}
+}
+
+// WithCredentials sets the client ID and client secret used to authorize.
+func WithCredentials(clientID, clientSecret string) Option {
+	return func(o *clientOptions) {
+		o.cfg.ClientID = clientID
+		o.cfg.ClientSecret = clientSecret
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. If httpClient is nil, http.DefaultClient will be used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+		o.applyTimeout()
+	}
+}
+
+// WithTimeout sets the timeout of every HTTP request, including reading the response body.
+// The HTTP client is copied, so a client passed to WithHTTPClient is not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+		o.applyTimeout()
+	}
+}
+
+func (o *clientOptions) applyTimeout() {
+	if o.timeout <= 0 {
+		return
+	}
+
+	c := http.Client{}
+	if o.httpClient != nil {
+		c = *o.httpClient
+	}
+
+	c.Timeout = o.timeout
+	o.httpClient = &c
+}
+
+// WithBaseURL sets the base URL of the bank API. See Config.BaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.cfg.BaseURL = baseURL
+	}
+}
+
+// WithAuthURL sets the token URL of the identity server. See Config.AuthURL.
+func WithAuthURL(authURL string) Option {
+	return func(o *clientOptions) {
+		o.cfg.AuthURL = authURL
+	}
+}
+
+// WithAPIVersion sets the API version path segment. See Config.APIVersion.
+func WithAPIVersion(version string) Option {
+	return func(o *clientOptions) {
+		o.cfg.APIVersion = version
+	}
+}
+
+// WithUserAgent sets the user agent of requests.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.cfg.UserAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets the retry policy of failed requests. See Config.RetryPolicy.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.cfg.RetryPolicy = p
+	}
+}
+
+// WithRateLimit limits the number of requests per second. See Config.RateLimit and Config.RateBurst.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *clientOptions) {
+		o.cfg.RateLimit = rps
+		o.cfg.RateBurst = burst
+	}
+}
+
+// WithRateLimiter sets a rate limiter that can be shared between clients. See Config.RateLimiter.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(o *clientOptions) {
+		o.cfg.RateLimiter = l
+	}
+}
+
+// WithLogger sets the logger for warnings and retried requests. See Config.Logger.
+func WithLogger(l Logger) Option {
+	return func(o *clientOptions) {
+		o.cfg.Logger = l
+	}
+}
+
+// WithTokenSource sets the source of access tokens. See Config.TokenSource.
+func WithTokenSource(src TokenSource) Option {
+	return func(o *clientOptions) {
+		o.cfg.TokenSource = src
+	}
+}
+
+// WithTokenRefreshSkew sets how long before expiry the access token is refreshed. See Config.TokenRefreshSkew.
+func WithTokenRefreshSkew(skew time.Duration) Option {
+	return func(o *clientOptions) {
+		o.cfg.TokenRefreshSkew = skew
+	}
+}
+
+// WithMiddleware appends middleware wrapping every request. See Config.Middleware.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *clientOptions) {
+		o.cfg.Middleware = append(o.cfg.Middleware[:len(o.cfg.Middleware):len(o.cfg.Middleware)], middleware...)
+	}
+}
+
+// WithMaxBodySize limits the size of response bodies in bytes. See Config.MaxBodySize.
+func WithMaxBodySize(n int64) Option {
+	return func(o *clientOptions) {
+		o.cfg.MaxBodySize = n
+	}
+}
+
+// WithLazyAuth defers authorization until the first request, instead of authorizing in the constructor.
+func WithLazyAuth() Option {
+	return func(o *clientOptions) {
+		o.cfg.LazyAuth = true
+	}
+}
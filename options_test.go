@@ -0,0 +1,158 @@
+package sbanken
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "some-client-id", "some-client-secret")
+
+	t.Run("should configure client", func(t *testing.T) {
+		c, err := NewClientWithOptions(ctx,
+			WithCredentials("some-client-id", "some-client-secret"),
+			WithHTTPClient(srv.Client()),
+			WithBaseURL(srv.URL+"/api"),
+			WithAuthURL(srv.URL+"/connect/token"),
+			WithAPIVersion("v2"),
+			WithUserAgent("test-agent"),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if c.bankBaseURL != srv.URL+"/api" || c.apiVersion != "v2" {
+			t.Errorf("unexpected client: got %s/%s", c.bankBaseURL, c.apiVersion)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should override config with later options", func(t *testing.T) {
+		c, err := NewClientWithOptions(ctx,
+			WithConfig(&Config{ClientID: "some-client-id", ClientSecret: "wrong-secret", APIVersion: "v1"}),
+			WithCredentials("some-client-id", "some-client-secret"),
+			WithHTTPClient(srv.Client()),
+			WithBaseURL(srv.URL+"/api"),
+			WithAuthURL(srv.URL+"/connect/token"),
+			WithAPIVersion("v2"),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not authorize with lazy auth", func(t *testing.T) {
+		_, err := NewClientWithOptions(ctx,
+			WithCredentials("some-client-id", "wrong-secret"),
+			WithHTTPClient(srv.Client()),
+			WithAuthURL(srv.URL+"/connect/token"),
+			WithLazyAuth(),
+		)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should validate options", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opts []Option
+			exp  error
+		}{
+			{name: "should require credentials", exp: ErrMissingClientID},
+			{name: "should validate config", opts: []Option{WithCredentials("id", "secret"), WithBaseURL("not a url")}, exp: ErrInvalidBaseURL},
+			{name: "should reject negative timeout", opts: []Option{WithCredentials("id", "secret"), WithTimeout(-time.Second)}, exp: ErrInvalidTimeout},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := NewClientWithOptions(ctx, tc.opts...); !errors.Is(err, tc.exp) {
+					t.Errorf("unexpected error: got %v, exp %v", err, tc.exp)
+				}
+			})
+		}
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	t.Cleanup(srv.Close)
+
+	httpClient := srv.Client()
+
+	c, err := NewClientWithOptions(ctx,
+		WithTokenSource(StaticTokenSource("test-token")),
+		WithTimeout(10*time.Millisecond),
+		WithHTTPClient(httpClient),
+		WithBaseURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.ListAccounts(ctx); !errors.Is(err, ErrTimeout) {
+		t.Errorf("unexpected error: got %v, exp %v", err, ErrTimeout)
+	}
+
+	if httpClient.Timeout != 0 {
+		t.Errorf("expected HTTP client not to be modified: got timeout %s", httpClient.Timeout)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var buf bytes.Buffer
+
+	c, err := NewClientWithOptions(ctx,
+		WithTokenSource(StaticTokenSource("test-token")),
+		WithHTTPClient(srv.Client()),
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithLogger(log.New(&buf, "", 0)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.ListAccounts(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "retrying GET") {
+		t.Errorf("expected retry to be logged: got %q", buf.String())
+	}
+}
@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,20 +12,20 @@ import (
 // Payment represents a payment.
 // Sbanken API documentation: https://publicapi.sbanken.no/openapi/apibeta/index.html#/Payments
 type Payment struct {
-	AllowedNewStatusTypes  []string `json:"allowedNewStatusTypes"`
-	ID                     string   `json:"paymentId"`
-	RecipientAccountNumber string   `json:"recipientAccountNumber"`
-	DueDate                string   `json:"dueDate"`
-	KID                    string   `json:"kid"`
-	Text                   string   `json:"text"`
-	Status                 string   `json:"status"`
-	StatusDetails          string   `json:"statusDetails"`
-	ProductType            string   `json:"productType"`
-	PaymentType            string   `json:"paymentType"`
-	BeneficiaryName        string   `json:"beneficiaryName"`
-	Amount                 float32  `json:"amount"`
-	PaymentNumber          int      `json:"paymentNumber"`
-	IsActive               bool     `json:"isActive"`
+	AllowedNewStatusTypes  []string      `json:"allowedNewStatusTypes"`
+	ID                     string        `json:"paymentId"`
+	RecipientAccountNumber AccountNumber `json:"recipientAccountNumber"`
+	DueDate                Date          `json:"dueDate"`
+	KID                    KID           `json:"kid"`
+	Text                   string        `json:"text"`
+	Status                 string        `json:"status"`
+	StatusDetails          string        `json:"statusDetails"`
+	ProductType            string        `json:"productType"`
+	PaymentType            string        `json:"paymentType"`
+	BeneficiaryName        string        `json:"beneficiaryName"`
+	Amount                 Money         `json:"amount"`
+	PaymentNumber          int           `json:"paymentNumber"`
+	IsActive               bool          `json:"isActive"`
 }
 
 // PaymentListQuery represents query parameters for querying payments.
@@ -55,13 +54,75 @@ func (q *PaymentListQuery) QueryString(u string) (string, error) {
 	return query.Encode(), nil
 }
 
+// PaymentPage represents a page of payments, together with the total number of available items and the trace ID of the response.
+type PaymentPage struct {
+	TraceID        string
+	Items          []Payment
+	AvailableItems int
+}
+
 // ListPayments list the payments. The accountID are required.
 func (c *Client) ListPayments(ctx context.Context, accountID string, q *PaymentListQuery) ([]Payment, error) {
+	page, err := c.ListPaymentsPage(ctx, accountID, q)
+	if page == nil {
+		return nil, err
+	}
+
+	return page.Items, err
+}
+
+// ListPaymentsPage is like ListPayments, but also returns the number of available items and the trace ID.
+func (c *Client) ListPaymentsPage(ctx context.Context, accountID string, q *PaymentListQuery) (*PaymentPage, error) {
 	if accountID == "" {
 		return nil, ErrMissingAccountID
 	}
 
-	url := fmt.Sprintf("%s/v1/Payments/%s", c.bankBaseURL, accountID)
+	return c.listPayments(ctx, accountID, q)
+}
+
+// IteratePayments returns an iterator over all payments of the given account.
+// Index and Length of the query are managed by the iterator.
+func (c *Client) IteratePayments(accountID string, q *PaymentListQuery, opts *PageOptions) *PaymentIterator {
+	var query PaymentListQuery
+	if q != nil {
+		query = *q
+	}
+
+	it := newIterator(query.Index, query.Length, opts, func(ctx context.Context, index, length string) ([]Payment, int, error) {
+		query.Index, query.Length = index, length
+
+		page, err := c.listPayments(ctx, accountID, &query)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return page.Items, page.AvailableItems, nil
+	})
+
+	if accountID == "" {
+		it.pager.err = ErrMissingAccountID
+	}
+
+	return &PaymentIterator{it}
+}
+
+// ListAllPayments fetches all payments of the given account, one page at a time.
+func (c *Client) ListAllPayments(ctx context.Context, accountID string, q *PaymentListQuery, opts *PageOptions) ([]Payment, error) {
+	return c.IteratePayments(accountID, q, opts).all(ctx)
+}
+
+// PaymentIterator iterates over payments across pages.
+type PaymentIterator struct {
+	*Iterator[Payment]
+}
+
+// Payment returns the current payment.
+func (it *PaymentIterator) Payment() Payment {
+	return it.Item()
+}
+
+func (c *Client) listPayments(ctx context.Context, accountID string, q *PaymentListQuery) (*PaymentPage, error) {
+	url := fmt.Sprintf("%s/%s/Payments/%s", c.bankBaseURL, c.apiVersion, accountID)
 
 	if q != nil {
 		qs, err := q.QueryString(url)
@@ -72,34 +133,19 @@ func (c *Client) ListPayments(ctx context.Context, accountID string, q *PaymentL
 		url = fmt.Sprintf("%s?%s", url, qs)
 	}
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Payment](ctx, c, "ListPayments", &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
-	}
-
-	data := struct {
-		Payments []Payment `json:"items"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Payments, fmt.Errorf("Unmarshal: %w", err)
+	if data == nil {
+		return nil, err
 	}
 
-	if data.IsError || sc != http.StatusOK {
-		return data.Payments, &Error{
-			"ListPayments",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
-	}
-
-	return data.Payments, nil
+	return &PaymentPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
 
 // ReadPayment reads a payment. The accountID and paymentID are required.
@@ -112,34 +158,15 @@ func (c *Client) ReadPayment(ctx context.Context, accountID string, paymentID st
 		return Payment{}, ErrMissingPaymentID
 	}
 
-	url := fmt.Sprintf("%s/v1/Payments/%s/%s", c.bankBaseURL, accountID, paymentID)
+	url := fmt.Sprintf("%s/%s/Payments/%s/%s", c.bankBaseURL, c.apiVersion, accountID, paymentID)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Payment](ctx, c, "ReadPayment", &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return Payment{}, fmt.Errorf("request: %w", err)
-	}
-
-	data := struct {
-		Payment Payment `json:"item"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Payment, fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return data.Payment, &Error{
-			"ReadPayment",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	if data == nil {
+		return Payment{}, err
 	}
 
-	return data.Payment, nil
+	return data.Item, err
 }
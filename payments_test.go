@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
-	"time"
 
 	"github.com/engvik/sbanken-go/internal/transport"
 )
@@ -14,7 +13,7 @@ import (
 var testPayment = Payment{
 	ID:                     "test-payment",
 	RecipientAccountNumber: "987654321",
-	DueDate:                time.Now().String(),
+	DueDate:                mustParseDate("2021-02-20T00:00:00"),
 	KID:                    "00000123456799",
 	Text:                   "Hello, yes, this is Payment!",
 	Status:                 "status",
@@ -23,7 +22,7 @@ var testPayment = Payment{
 	ProductType:            "product",
 	PaymentType:            "payment",
 	BeneficiaryName:        "name nameson",
-	Amount:                 1337.00,
+	Amount:                 133700,
 	PaymentNumber:          4,
 	IsActive:               true,
 }
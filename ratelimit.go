@@ -0,0 +1,11 @@
+package sbanken
+
+import "github.com/engvik/sbanken-go/internal/transport"
+
+// RateLimiter is a token bucket rate limiter that can be shared between clients through Config.RateLimiter.
+type RateLimiter = transport.RateLimiter
+
+// NewRateLimiter returns a rate limiter allowing rps requests per second on average, with bursts of up to burst requests.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return transport.NewRateLimiter(rps, burst)
+}
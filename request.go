@@ -0,0 +1,51 @@
+package sbanken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+// apiResponse is the response envelope of the API. Endpoints returning a single
+// resource set Item, and endpoints returning a list set Items.
+type apiResponse[T any] struct {
+	Item  T   `json:"item"`
+	Items []T `json:"items"`
+	transport.HTTPResponse
+}
+
+// do performs the request and decodes the response. Responses other than 200 OK, or 204 No Content without
+// a body, are errors. Errors are returned as *Error, prefixed by caller,
+// which is also used as the operation name unless the request sets one. The decoded response is
+// returned along with errors reported by the API, so that callers can return partial results.
+func do[T any](ctx context.Context, c *Client, caller string, r *transport.HTTPRequest) (*apiResponse[T], error) {
+	if r.Operation == "" {
+		r.Operation = caller
+	}
+
+	res, sc, err := c.transport.Request(ctx, r)
+	if err != nil {
+		return nil, newRequestError(caller, err)
+	}
+
+	var data apiResponse[T]
+
+	// A 200 OK response must have a body. Only 204 No Content responses, such as to DELETE requests,
+	// succeed without one, while error responses without a body are reported by their status code.
+	if len(res) > 0 || sc == http.StatusOK {
+		if err := json.Unmarshal(res, &data); err != nil {
+			return nil, newDecodeError(caller, fmt.Errorf("Unmarshal: %w", err), sc)
+		}
+	}
+
+	noContent := sc == http.StatusNoContent && len(res) == 0
+
+	if data.IsError || (sc != http.StatusOK && !noContent) {
+		return &data, newResponseError(caller, data.HTTPResponse, sc)
+	}
+
+	return &data, nil
+}
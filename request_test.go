@@ -0,0 +1,151 @@
+package sbanken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+type testResponseTransportClient struct {
+	err     error
+	body    []byte
+	sc      int
+	request *transport.HTTPRequest
+}
+
+func (c *testResponseTransportClient) Authorize(ctx context.Context) error {
+	return nil
+}
+
+func (c *testResponseTransportClient) Request(ctx context.Context, r *transport.HTTPRequest) ([]byte, int, error) {
+	c.request = r
+
+	return c.body, c.sc, c.err
+}
+
+func TestDo(t *testing.T) {
+	errRequest := errors.New("request failed")
+
+	tests := []struct {
+		name        string
+		transport   *testResponseTransportClient
+		expItem     string
+		expTraceID  string
+		expData     bool
+		expErr      bool
+		expErrType  bool
+		expErrCause error
+		expSentinel error
+	}{
+		{
+			name:       "should decode item",
+			transport:  &testResponseTransportClient{body: []byte(`{"item":"value","traceId":"trace"}`), sc: http.StatusOK},
+			expItem:    "value",
+			expTraceID: "trace",
+			expData:    true,
+		},
+		{
+			name:      "should accept no content",
+			transport: &testResponseTransportClient{sc: http.StatusNoContent},
+			expData:   true,
+		},
+		{
+			name:       "should return error on empty body",
+			transport:  &testResponseTransportClient{sc: http.StatusOK},
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:       "should return error on status code without body",
+			transport:  &testResponseTransportClient{sc: http.StatusTooManyRequests},
+			expData:    true,
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:       "should return error on other 2xx status code",
+			transport:  &testResponseTransportClient{body: []byte(`{}`), sc: http.StatusAccepted},
+			expData:    true,
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:       "should return response with API error",
+			transport:  &testResponseTransportClient{body: []byte(`{"isError":true,"errorType":"Input","traceId":"trace"}`), sc: http.StatusOK},
+			expTraceID: "trace",
+			expData:    true,
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:       "should return error on non 2xx status code",
+			transport:  &testResponseTransportClient{body: []byte(`{}`), sc: http.StatusConflict},
+			expData:    true,
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:       "should return error on invalid body",
+			transport:  &testResponseTransportClient{body: []byte(`<html>`), sc: http.StatusOK},
+			expErr:     true,
+			expErrType: true,
+		},
+		{
+			name:        "should keep status code of invalid body",
+			transport:   &testResponseTransportClient{body: []byte(`{"isError":`), sc: http.StatusServiceUnavailable},
+			expErr:      true,
+			expErrType:  true,
+			expSentinel: ErrServiceUnavailable,
+		},
+		{
+			name:        "should wrap request errors",
+			transport:   &testResponseTransportClient{err: errRequest},
+			expErr:      true,
+			expErrType:  true,
+			expErrCause: errRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Client{transport: tc.transport}
+
+			data, err := do[string](context.Background(), c, "Test", &transport.HTTPRequest{
+				Method: http.MethodDelete,
+				URL:    "https://example.com",
+			})
+
+			if (err != nil) != tc.expErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var sErr *Error
+			if errors.As(err, &sErr) != tc.expErrType {
+				t.Errorf("unexpected error type: got %T", err)
+			}
+
+			if tc.expErrCause != nil && !errors.Is(err, tc.expErrCause) {
+				t.Errorf("unexpected error cause: got %v, exp %v", err, tc.expErrCause)
+			}
+
+			if tc.expSentinel != nil && !errors.Is(err, tc.expSentinel) {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.expSentinel)
+			}
+
+			if (data != nil) != tc.expData {
+				t.Fatalf("unexpected data: got %+v", data)
+			}
+
+			if data != nil && (data.Item != tc.expItem || data.TraceID != tc.expTraceID) {
+				t.Errorf("unexpected data: got %+v", data)
+			}
+
+			if tc.transport.request.Operation != "Test" {
+				t.Errorf("unexpected operation: got %q, exp %q", tc.transport.request.Operation, "Test")
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package sbanken
+
+import "github.com/engvik/sbanken-go/internal/transport"
+
+// RetryPolicy configures retries of failed requests. Set it on Config to enable retries.
+//
+// Only idempotent requests such as GET are retried, unless RetryNonIdempotent
+// is set. Transfer and PayEfaktura are POST requests, and retrying them may
+// execute the transfer or payment twice.
+//
+// A request rejected with 401 Unauthorized is sent once more with a new token,
+// which does not count against MaxAttempts. This also applies without a policy,
+// but POST requests are only sent again if RetryNonIdempotent is set.
+type RetryPolicy = transport.RetryPolicy
+
+// RetryAttempt describes a single attempt of a request, as passed to RetryPolicy.OnAttempt.
+type RetryAttempt = transport.Attempt
+
+// DefaultShouldRetry retries temporary network and authorization errors,
+// 429 Too Many Requests and 5xx responses except 501 Not Implemented.
+// Cancelled requests and TLS failures are not retried.
+func DefaultShouldRetry(statusCode int, err error) bool {
+	return transport.DefaultShouldRetry(statusCode, err)
+}
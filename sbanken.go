@@ -22,11 +22,17 @@ type transportClient interface {
 // Client represents an Sbanken client.
 type Client struct {
 	bankBaseURL string
+	apiVersion  string
 	transport   transportClient
 }
 
 // NewClient returns a new Sbanken client. If httpClient is nil, http.DefaultClient will be used.
+// It is equivalent to NewClientWithOptions with WithConfig and WithHTTPClient.
 func NewClient(ctx context.Context, cfg *Config, httpClient *http.Client) (*Client, error) {
+	return NewClientWithOptions(ctx, WithConfig(cfg), WithHTTPClient(httpClient))
+}
+
+func newClient(ctx context.Context, cfg *Config, httpClient *http.Client) (*Client, error) {
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("validate: %w", err)
 	}
@@ -37,17 +43,26 @@ func NewClient(ctx context.Context, cfg *Config, httpClient *http.Client) (*Clie
 	}
 
 	tCfg := &transport.Config{
-		ClientID:     cfg.ClientID,
-		ClientSecret: cfg.ClientSecret,
-		UserAgent:    userAgent,
+		ClientID:         cfg.ClientID,
+		ClientSecret:     cfg.ClientSecret,
+		UserAgent:        userAgent,
+		AuthURL:          cfg.authURL(),
+		TokenSource:      cfg.TokenSource,
+		TokenRefreshSkew: cfg.TokenRefreshSkew,
+		RetryPolicy:      cfg.RetryPolicy,
+		RateLimiter:      cfg.rateLimiter(),
+		MaxBodySize:      cfg.MaxBodySize,
+		Middleware:       cfg.Middleware,
+		Logger:           cfg.Logger,
 	}
 
 	c := &Client{
-		bankBaseURL: "https://publicapi.sbanken.no/apibeta/api",
+		bankBaseURL: cfg.baseURL(),
+		apiVersion:  cfg.apiVersion(),
 		transport:   transport.New(ctx, tCfg, httpClient),
 	}
 
-	if !cfg.skipAuth {
+	if !cfg.LazyAuth {
 		if err := c.transport.Authorize(ctx); err != nil {
 			return c, fmt.Errorf("Authorize: %w", err)
 		}
@@ -55,3 +70,18 @@ func NewClient(ctx context.Context, cfg *Config, httpClient *http.Client) (*Clie
 
 	return c, nil
 }
+
+// Ping verifies the credentials and that the API is reachable, by fetching an access token
+// if needed and listing the accounts. It is suitable for health checks, and for verifying
+// the credentials of a client created with LazyAuth. Failures to authorize are returned as
+// an *AuthError, and failures to reach the API as a *NetworkError, wrapped in an *Error.
+func (c *Client) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/%s/Accounts", c.bankBaseURL, c.apiVersion)
+
+	_, err := do[Account](ctx, c, "Ping", &transport.HTTPRequest{
+		Method: http.MethodGet,
+		URL:    url,
+	})
+
+	return err
+}
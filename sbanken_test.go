@@ -2,6 +2,10 @@ package sbanken
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/engvik/sbanken-go/internal/transport"
@@ -105,7 +109,7 @@ func newTestClient(ctx context.Context, t *testing.T) (*Client, error) {
 	cfg := &Config{
 		ClientID:     "some-client-id",
 		ClientSecret: "some-client-secret",
-		skipAuth:     true,
+		LazyAuth:     true,
 	}
 
 	c, err := NewClient(ctx, cfg, nil)
@@ -133,9 +137,206 @@ func TestNewClient(t *testing.T) {
 		}
 	})
 
+	t.Run("should have apiVersion set", func(t *testing.T) {
+		if c.apiVersion != DefaultAPIVersion {
+			t.Errorf("unexpected apiVersion: got %s, exp %s", c.apiVersion, DefaultAPIVersion)
+		}
+	})
+
 	t.Run("should have transport set", func(t *testing.T) {
 		if c.transport == nil {
 			t.Errorf("expected transport to be set")
 		}
 	})
 }
+
+func newTestServer(t *testing.T, clientID, clientSecret string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != clientID || secret != clientSecret || r.Method != http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	})
+
+	mux.HandleFunc("/api/v2/Accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"availableItems": 1,
+			"items":          []Account{testAccount},
+		})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestNewClientWithTestServer(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "some-client-id", "some-client-secret")
+
+	t.Run("should use configured URLs and API version", func(t *testing.T) {
+		c, err := NewClient(ctx, &Config{
+			ClientID:     "some-client-id",
+			ClientSecret: "some-client-secret",
+			BaseURL:      srv.URL + "/api/",
+			AuthURL:      srv.URL + "/connect/token",
+			APIVersion:   "v2",
+		}, srv.Client())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		accounts, err := c.ListAccounts(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(accounts) != 1 || accounts[0].ID != testAccount.ID {
+			t.Errorf("unexpected accounts: got %v", accounts)
+		}
+	})
+
+	t.Run("should fail to authorize with wrong credentials", func(t *testing.T) {
+		_, err := NewClient(ctx, &Config{
+			ClientID:     "some-client-id",
+			ClientSecret: "wrong-secret",
+			BaseURL:      srv.URL + "/api",
+			AuthURL:      srv.URL + "/connect/token",
+		}, srv.Client())
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) || !authErr.InvalidCredentials() {
+			t.Fatalf("unexpected error: got %v", err)
+		}
+	})
+
+	t.Run("should return auth error when refreshing token", func(t *testing.T) {
+		cfg := &Config{
+			ClientID:     "some-client-id",
+			ClientSecret: "expired-secret",
+			BaseURL:      srv.URL + "/api",
+			AuthURL:      srv.URL + "/connect/token",
+			APIVersion:   "v2",
+			LazyAuth:     true,
+		}
+
+		c, err := NewClient(ctx, cfg, srv.Client())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = c.ListAccounts(ctx)
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) || authErr.Code != "invalid_client" {
+			t.Fatalf("unexpected error: got %v", err)
+		}
+	})
+}
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "some-client-id", "some-client-secret")
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	tests := []struct {
+		name         string
+		clientSecret string
+		baseURL      string
+		authURL      string
+		expErr       error
+		expAuthErr   bool
+	}{
+		{
+			name:         "should succeed with valid credentials",
+			clientSecret: "some-client-secret",
+		},
+		{
+			name:         "should return auth error with invalid credentials",
+			clientSecret: "wrong-secret",
+			expAuthErr:   true,
+		},
+		{
+			name:         "should return network error when identity server is down",
+			clientSecret: "some-client-secret",
+			authURL:      downURL + "/connect/token",
+			expErr:       ErrConnectionRefused,
+		},
+		{
+			name:         "should return network error when API is down",
+			clientSecret: "some-client-secret",
+			baseURL:      downURL + "/api",
+			expErr:       ErrConnectionRefused,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				ClientID:     "some-client-id",
+				ClientSecret: tc.clientSecret,
+				BaseURL:      srv.URL + "/api",
+				AuthURL:      srv.URL + "/connect/token",
+				APIVersion:   "v2",
+				LazyAuth:     true,
+			}
+
+			if tc.baseURL != "" {
+				cfg.BaseURL = tc.baseURL
+			}
+
+			if tc.authURL != "" {
+				cfg.AuthURL = tc.authURL
+			}
+
+			c, err := NewClient(ctx, cfg, srv.Client())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = c.Ping(ctx)
+
+			if tc.expErr == nil && !tc.expAuthErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var sErr *Error
+			if !errors.As(err, &sErr) || sErr.ErrorString != "Ping" {
+				t.Errorf("unexpected error: got %v", err)
+			}
+
+			if tc.expErr != nil && !errors.Is(err, tc.expErr) {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			var authErr *AuthError
+			if tc.expAuthErr && !errors.As(err, &authErr) {
+				t.Errorf("expected AuthError: got %v", err)
+			}
+		})
+	}
+}
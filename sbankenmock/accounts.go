@@ -0,0 +1,43 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// AccountsService is a mock of sbanken.AccountsService.
+type AccountsService struct {
+	ListAccountsFunc     func(ctx context.Context) ([]sbanken.Account, error)
+	ListAccountsPageFunc func(ctx context.Context) (*sbanken.AccountPage, error)
+	ReadAccountFunc      func(ctx context.Context, accountID string) (sbanken.Account, error)
+}
+
+var _ sbanken.AccountsService = (*AccountsService)(nil)
+
+// ListAccounts calls ListAccountsFunc.
+func (m *AccountsService) ListAccounts(ctx context.Context) ([]sbanken.Account, error) {
+	if m.ListAccountsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAccountsFunc(ctx)
+}
+
+// ListAccountsPage calls ListAccountsPageFunc.
+func (m *AccountsService) ListAccountsPage(ctx context.Context) (*sbanken.AccountPage, error) {
+	if m.ListAccountsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAccountsPageFunc(ctx)
+}
+
+// ReadAccount calls ReadAccountFunc.
+func (m *AccountsService) ReadAccount(ctx context.Context, accountID string) (sbanken.Account, error) {
+	if m.ReadAccountFunc == nil {
+		return sbanken.Account{}, ErrNotImplemented
+	}
+
+	return m.ReadAccountFunc(ctx, accountID)
+}
@@ -0,0 +1,33 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// CardsService is a mock of sbanken.CardsService.
+type CardsService struct {
+	ListCardsFunc     func(ctx context.Context) ([]sbanken.Card, error)
+	ListCardsPageFunc func(ctx context.Context) (*sbanken.CardPage, error)
+}
+
+var _ sbanken.CardsService = (*CardsService)(nil)
+
+// ListCards calls ListCardsFunc.
+func (m *CardsService) ListCards(ctx context.Context) ([]sbanken.Card, error) {
+	if m.ListCardsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListCardsFunc(ctx)
+}
+
+// ListCardsPage calls ListCardsPageFunc.
+func (m *CardsService) ListCardsPage(ctx context.Context) (*sbanken.CardPage, error) {
+	if m.ListCardsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListCardsPageFunc(ctx)
+}
@@ -0,0 +1,23 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// CustomersService is a mock of sbanken.CustomersService.
+type CustomersService struct {
+	GetCustomerFunc func(ctx context.Context) (sbanken.Customer, error)
+}
+
+var _ sbanken.CustomersService = (*CustomersService)(nil)
+
+// GetCustomer calls GetCustomerFunc.
+func (m *CustomersService) GetCustomer(ctx context.Context) (sbanken.Customer, error) {
+	if m.GetCustomerFunc == nil {
+		return sbanken.Customer{}, ErrNotImplemented
+	}
+
+	return m.GetCustomerFunc(ctx)
+}
@@ -0,0 +1,93 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// EfakturasService is a mock of sbanken.EfakturasService.
+type EfakturasService struct {
+	ListEfakturasFunc        func(ctx context.Context, q *sbanken.EfakturaListQuery) ([]sbanken.Efaktura, error)
+	ListEfakturasPageFunc    func(ctx context.Context, q *sbanken.EfakturaListQuery) (*sbanken.EfakturaPage, error)
+	ListAllEfakturasFunc     func(ctx context.Context, q *sbanken.EfakturaListQuery, opts *sbanken.PageOptions) ([]sbanken.Efaktura, error)
+	ListNewEfakturasFunc     func(ctx context.Context, q *sbanken.EfakturaListQuery) ([]sbanken.Efaktura, error)
+	ListNewEfakturasPageFunc func(ctx context.Context, q *sbanken.EfakturaListQuery) (*sbanken.EfakturaPage, error)
+	ListAllNewEfakturasFunc  func(ctx context.Context, q *sbanken.EfakturaListQuery, opts *sbanken.PageOptions) ([]sbanken.Efaktura, error)
+	ReadEfakturaFunc         func(ctx context.Context, efakturaID string) (sbanken.Efaktura, error)
+	PayEfakturaFunc          func(ctx context.Context, q *sbanken.EfakturaPayQuery) error
+}
+
+var _ sbanken.EfakturasService = (*EfakturasService)(nil)
+
+// ListEfakturas calls ListEfakturasFunc.
+func (m *EfakturasService) ListEfakturas(ctx context.Context, q *sbanken.EfakturaListQuery) ([]sbanken.Efaktura, error) {
+	if m.ListEfakturasFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListEfakturasFunc(ctx, q)
+}
+
+// ListEfakturasPage calls ListEfakturasPageFunc.
+func (m *EfakturasService) ListEfakturasPage(ctx context.Context, q *sbanken.EfakturaListQuery) (*sbanken.EfakturaPage, error) {
+	if m.ListEfakturasPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListEfakturasPageFunc(ctx, q)
+}
+
+// ListAllEfakturas calls ListAllEfakturasFunc.
+func (m *EfakturasService) ListAllEfakturas(ctx context.Context, q *sbanken.EfakturaListQuery, opts *sbanken.PageOptions) ([]sbanken.Efaktura, error) {
+	if m.ListAllEfakturasFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAllEfakturasFunc(ctx, q, opts)
+}
+
+// ListNewEfakturas calls ListNewEfakturasFunc.
+func (m *EfakturasService) ListNewEfakturas(ctx context.Context, q *sbanken.EfakturaListQuery) ([]sbanken.Efaktura, error) {
+	if m.ListNewEfakturasFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListNewEfakturasFunc(ctx, q)
+}
+
+// ListNewEfakturasPage calls ListNewEfakturasPageFunc.
+func (m *EfakturasService) ListNewEfakturasPage(ctx context.Context, q *sbanken.EfakturaListQuery) (*sbanken.EfakturaPage, error) {
+	if m.ListNewEfakturasPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListNewEfakturasPageFunc(ctx, q)
+}
+
+// ListAllNewEfakturas calls ListAllNewEfakturasFunc.
+func (m *EfakturasService) ListAllNewEfakturas(ctx context.Context, q *sbanken.EfakturaListQuery, opts *sbanken.PageOptions) ([]sbanken.Efaktura, error) {
+	if m.ListAllNewEfakturasFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAllNewEfakturasFunc(ctx, q, opts)
+}
+
+// ReadEfaktura calls ReadEfakturaFunc.
+func (m *EfakturasService) ReadEfaktura(ctx context.Context, efakturaID string) (sbanken.Efaktura, error) {
+	if m.ReadEfakturaFunc == nil {
+		return sbanken.Efaktura{}, ErrNotImplemented
+	}
+
+	return m.ReadEfakturaFunc(ctx, efakturaID)
+}
+
+// PayEfaktura calls PayEfakturaFunc.
+func (m *EfakturasService) PayEfaktura(ctx context.Context, q *sbanken.EfakturaPayQuery) error {
+	if m.PayEfakturaFunc == nil {
+		return ErrNotImplemented
+	}
+
+	return m.PayEfakturaFunc(ctx, q)
+}
@@ -0,0 +1,53 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// PaymentsService is a mock of sbanken.PaymentsService.
+type PaymentsService struct {
+	ListPaymentsFunc     func(ctx context.Context, accountID string, q *sbanken.PaymentListQuery) ([]sbanken.Payment, error)
+	ListPaymentsPageFunc func(ctx context.Context, accountID string, q *sbanken.PaymentListQuery) (*sbanken.PaymentPage, error)
+	ListAllPaymentsFunc  func(ctx context.Context, accountID string, q *sbanken.PaymentListQuery, opts *sbanken.PageOptions) ([]sbanken.Payment, error)
+	ReadPaymentFunc      func(ctx context.Context, accountID string, paymentID string) (sbanken.Payment, error)
+}
+
+var _ sbanken.PaymentsService = (*PaymentsService)(nil)
+
+// ListPayments calls ListPaymentsFunc.
+func (m *PaymentsService) ListPayments(ctx context.Context, accountID string, q *sbanken.PaymentListQuery) ([]sbanken.Payment, error) {
+	if m.ListPaymentsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListPaymentsFunc(ctx, accountID, q)
+}
+
+// ListPaymentsPage calls ListPaymentsPageFunc.
+func (m *PaymentsService) ListPaymentsPage(ctx context.Context, accountID string, q *sbanken.PaymentListQuery) (*sbanken.PaymentPage, error) {
+	if m.ListPaymentsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListPaymentsPageFunc(ctx, accountID, q)
+}
+
+// ListAllPayments calls ListAllPaymentsFunc.
+func (m *PaymentsService) ListAllPayments(ctx context.Context, accountID string, q *sbanken.PaymentListQuery, opts *sbanken.PageOptions) ([]sbanken.Payment, error) {
+	if m.ListAllPaymentsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAllPaymentsFunc(ctx, accountID, q, opts)
+}
+
+// ReadPayment calls ReadPaymentFunc.
+func (m *PaymentsService) ReadPayment(ctx context.Context, accountID string, paymentID string) (sbanken.Payment, error) {
+	if m.ReadPaymentFunc == nil {
+		return sbanken.Payment{}, ErrNotImplemented
+	}
+
+	return m.ReadPaymentFunc(ctx, accountID, paymentID)
+}
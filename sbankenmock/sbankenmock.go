@@ -0,0 +1,47 @@
+// Package sbankenmock provides mocks of the sbanken service interfaces, for testing code
+// depending on an Sbanken client without calling the API.
+//
+// Every mock has a function field per method, named after the method with a Func suffix.
+// Calling a method without its function set returns ErrNotImplemented.
+//
+//	accounts := &sbankenmock.AccountsService{
+//		ListAccountsFunc: func(ctx context.Context) ([]sbanken.Account, error) {
+//			return []sbanken.Account{{ID: "account-id"}}, nil
+//		},
+//	}
+package sbankenmock
+
+import (
+	"context"
+	"errors"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// ErrNotImplemented are returned when calling a method of a mock without its function set.
+var ErrNotImplemented = errors.New("sbankenmock: method not implemented")
+
+// Client is a mock of sbanken.API, made up of the mocks of every service.
+type Client struct {
+	AccountsService
+	CardsService
+	CustomersService
+	EfakturasService
+	PaymentsService
+	StandingOrdersService
+	TransactionsService
+	TransfersService
+
+	PingFunc func(ctx context.Context) error
+}
+
+var _ sbanken.API = (*Client)(nil)
+
+// Ping calls PingFunc.
+func (m *Client) Ping(ctx context.Context) error {
+	if m.PingFunc == nil {
+		return ErrNotImplemented
+	}
+
+	return m.PingFunc(ctx)
+}
@@ -0,0 +1,106 @@
+package sbankenmock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// sumBalances is an example of code depending on a service interface.
+func sumBalances(ctx context.Context, s sbanken.AccountsService) (sbanken.Money, error) {
+	accounts, err := s.ListAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum sbanken.Money
+	for _, a := range accounts {
+		sum = sum.Add(a.Balance)
+	}
+
+	return sum, nil
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	errTest := errors.New("test error")
+
+	tests := []struct {
+		name   string
+		client *Client
+		exp    sbanken.Money
+		expErr error
+	}{
+		{
+			name:   "should return ErrNotImplemented when func is not set",
+			client: &Client{},
+			expErr: ErrNotImplemented,
+		},
+		{
+			name: "should call func",
+			client: &Client{
+				AccountsService: AccountsService{
+					ListAccountsFunc: func(ctx context.Context) ([]sbanken.Account, error) {
+						return []sbanken.Account{{Balance: 100}, {Balance: 250}}, nil
+					},
+				},
+			},
+			exp: 350,
+		},
+		{
+			name: "should return error from func",
+			client: &Client{
+				AccountsService: AccountsService{
+					ListAccountsFunc: func(ctx context.Context) ([]sbanken.Account, error) {
+						return nil, errTest
+					},
+				},
+			},
+			expErr: errTest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := sumBalances(ctx, tc.client)
+
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if got != tc.exp {
+				t.Errorf("unexpected sum: got %v, exp %v", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestNotImplemented(t *testing.T) {
+	ctx := context.Background()
+	c := &Client{}
+
+	// Every method of the API interface should return ErrNotImplemented on a zero mock.
+	v := reflect.ValueOf(sbanken.API(c))
+	typ := v.Type()
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+
+		t.Run("should return ErrNotImplemented from "+m.Name, func(t *testing.T) {
+			in := []reflect.Value{reflect.ValueOf(ctx)}
+			for j := 2; j < m.Type.NumIn(); j++ {
+				in = append(in, reflect.Zero(m.Type.In(j)))
+			}
+
+			out := v.Method(i).Call(in)
+
+			err, _ := out[len(out)-1].Interface().(error)
+			if !errors.Is(err, ErrNotImplemented) {
+				t.Errorf("unexpected error: got %v, exp %v", err, ErrNotImplemented)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// StandingOrdersService is a mock of sbanken.StandingOrdersService.
+type StandingOrdersService struct {
+	ListStandingOrdersFunc     func(ctx context.Context, accountID string) ([]sbanken.StandingOrder, error)
+	ListStandingOrdersPageFunc func(ctx context.Context, accountID string) (*sbanken.StandingOrderPage, error)
+}
+
+var _ sbanken.StandingOrdersService = (*StandingOrdersService)(nil)
+
+// ListStandingOrders calls ListStandingOrdersFunc.
+func (m *StandingOrdersService) ListStandingOrders(ctx context.Context, accountID string) ([]sbanken.StandingOrder, error) {
+	if m.ListStandingOrdersFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListStandingOrdersFunc(ctx, accountID)
+}
+
+// ListStandingOrdersPage calls ListStandingOrdersPageFunc.
+func (m *StandingOrdersService) ListStandingOrdersPage(ctx context.Context, accountID string) (*sbanken.StandingOrderPage, error) {
+	if m.ListStandingOrdersPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListStandingOrdersPageFunc(ctx, accountID)
+}
@@ -0,0 +1,93 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// TransactionsService is a mock of sbanken.TransactionsService.
+type TransactionsService struct {
+	ListTransactionsFunc             func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) ([]sbanken.Transaction, error)
+	ListTransactionsPageFunc         func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) (*sbanken.TransactionPage, error)
+	ListAllTransactionsFunc          func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, opts *sbanken.PageOptions) ([]sbanken.Transaction, error)
+	StreamTransactionsFunc           func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, fn func(sbanken.Transaction) error) (*sbanken.TransactionPage, error)
+	ListArchivedTransactionsFunc     func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) ([]sbanken.Transaction, error)
+	ListArchivedTransactionsPageFunc func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) (*sbanken.TransactionPage, error)
+	ListAllArchivedTransactionsFunc  func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, opts *sbanken.PageOptions) ([]sbanken.Transaction, error)
+	StreamArchivedTransactionsFunc   func(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, fn func(sbanken.Transaction) error) (*sbanken.TransactionPage, error)
+}
+
+var _ sbanken.TransactionsService = (*TransactionsService)(nil)
+
+// ListTransactions calls ListTransactionsFunc.
+func (m *TransactionsService) ListTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) ([]sbanken.Transaction, error) {
+	if m.ListTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListTransactionsFunc(ctx, accountID, q)
+}
+
+// ListTransactionsPage calls ListTransactionsPageFunc.
+func (m *TransactionsService) ListTransactionsPage(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) (*sbanken.TransactionPage, error) {
+	if m.ListTransactionsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListTransactionsPageFunc(ctx, accountID, q)
+}
+
+// ListAllTransactions calls ListAllTransactionsFunc.
+func (m *TransactionsService) ListAllTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, opts *sbanken.PageOptions) ([]sbanken.Transaction, error) {
+	if m.ListAllTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAllTransactionsFunc(ctx, accountID, q, opts)
+}
+
+// StreamTransactions calls StreamTransactionsFunc.
+func (m *TransactionsService) StreamTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, fn func(sbanken.Transaction) error) (*sbanken.TransactionPage, error) {
+	if m.StreamTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.StreamTransactionsFunc(ctx, accountID, q, fn)
+}
+
+// ListArchivedTransactions calls ListArchivedTransactionsFunc.
+func (m *TransactionsService) ListArchivedTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) ([]sbanken.Transaction, error) {
+	if m.ListArchivedTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListArchivedTransactionsFunc(ctx, accountID, q)
+}
+
+// ListArchivedTransactionsPage calls ListArchivedTransactionsPageFunc.
+func (m *TransactionsService) ListArchivedTransactionsPage(ctx context.Context, accountID string, q *sbanken.TransactionListQuery) (*sbanken.TransactionPage, error) {
+	if m.ListArchivedTransactionsPageFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListArchivedTransactionsPageFunc(ctx, accountID, q)
+}
+
+// ListAllArchivedTransactions calls ListAllArchivedTransactionsFunc.
+func (m *TransactionsService) ListAllArchivedTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, opts *sbanken.PageOptions) ([]sbanken.Transaction, error) {
+	if m.ListAllArchivedTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.ListAllArchivedTransactionsFunc(ctx, accountID, q, opts)
+}
+
+// StreamArchivedTransactions calls StreamArchivedTransactionsFunc.
+func (m *TransactionsService) StreamArchivedTransactions(ctx context.Context, accountID string, q *sbanken.TransactionListQuery, fn func(sbanken.Transaction) error) (*sbanken.TransactionPage, error) {
+	if m.StreamArchivedTransactionsFunc == nil {
+		return nil, ErrNotImplemented
+	}
+
+	return m.StreamArchivedTransactionsFunc(ctx, accountID, q, fn)
+}
@@ -0,0 +1,23 @@
+package sbankenmock
+
+import (
+	"context"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// TransfersService is a mock of sbanken.TransfersService.
+type TransfersService struct {
+	TransferFunc func(ctx context.Context, q *sbanken.TransferQuery) error
+}
+
+var _ sbanken.TransfersService = (*TransfersService)(nil)
+
+// Transfer calls TransferFunc.
+func (m *TransfersService) Transfer(ctx context.Context, q *sbanken.TransferQuery) error {
+	if m.TransferFunc == nil {
+		return ErrNotImplemented
+	}
+
+	return m.TransferFunc(ctx, q)
+}
@@ -0,0 +1,474 @@
+package sbankentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/engvik/sbanken-go"
+)
+
+const (
+	// EfakturaStatusNew is the status of efakturas that have not been processed by the customer.
+	EfakturaStatusNew = "NEW"
+	// EfakturaStatusProcessed is the status of efakturas that have been paid.
+	EfakturaStatusProcessed = "PROCESSED"
+)
+
+// bank is the in-memory state of the Server. Items are kept in the order they were added.
+type bank struct {
+	customer       *sbanken.Customer
+	accounts       []*sbanken.Account
+	transactions   map[string][]sbanken.Transaction
+	payments       map[string][]sbanken.Payment
+	standingOrders map[string][]sbanken.StandingOrder
+	efakturas      []*sbanken.Efaktura
+	cards          []sbanken.Card
+	seq            int
+}
+
+func newBank() bank {
+	return bank{
+		transactions:   map[string][]sbanken.Transaction{},
+		payments:       map[string][]sbanken.Payment{},
+		standingOrders: map[string][]sbanken.StandingOrder{},
+	}
+}
+
+// AddAccounts adds accounts to the bank. Accounts with the ID of an existing account replace it.
+func (s *Server) AddAccounts(accounts ...sbanken.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range accounts {
+		a := a
+
+		if existing := s.bank.account(a.ID); existing != nil {
+			*existing = a
+			continue
+		}
+
+		s.bank.accounts = append(s.bank.accounts, &a)
+	}
+}
+
+// AddTransactions adds transactions to an account. The balances are not changed.
+func (s *Server) AddTransactions(accountID string, transactions ...sbanken.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bank.transactions[accountID] = append(s.bank.transactions[accountID], transactions...)
+}
+
+// AddPayments adds payments to an account.
+func (s *Server) AddPayments(accountID string, payments ...sbanken.Payment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bank.payments[accountID] = append(s.bank.payments[accountID], payments...)
+}
+
+// AddStandingOrders adds standing orders to an account.
+func (s *Server) AddStandingOrders(accountID string, orders ...sbanken.StandingOrder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bank.standingOrders[accountID] = append(s.bank.standingOrders[accountID], orders...)
+}
+
+// AddEfakturas adds efakturas to the bank. Efakturas without a status get EfakturaStatusNew.
+func (s *Server) AddEfakturas(efakturas ...sbanken.Efaktura) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range efakturas {
+		e := e
+
+		if e.Status == "" {
+			e.Status = EfakturaStatusNew
+		}
+
+		s.bank.efakturas = append(s.bank.efakturas, &e)
+	}
+}
+
+// AddCards adds cards to the bank.
+func (s *Server) AddCards(cards ...sbanken.Card) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bank.cards = append(s.bank.cards, cards...)
+}
+
+// SetCustomer sets the customer of the bank.
+func (s *Server) SetCustomer(customer sbanken.Customer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bank.customer = &customer
+}
+
+// Account returns the current state of an account.
+func (s *Server) Account(accountID string) (sbanken.Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.bank.account(accountID)
+	if a == nil {
+		return sbanken.Account{}, false
+	}
+
+	return *a, true
+}
+
+// Transactions returns the transactions of an account, including those recorded for transfers and payments.
+func (s *Server) Transactions(accountID string) []sbanken.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]sbanken.Transaction{}, s.bank.transactions[accountID]...)
+}
+
+// Efaktura returns the current state of an efaktura.
+func (s *Server) Efaktura(efakturaID string) (sbanken.Efaktura, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.bank.efaktura(efakturaID)
+	if e == nil {
+		return sbanken.Efaktura{}, false
+	}
+
+	return *e, true
+}
+
+func (b *bank) account(accountID string) *sbanken.Account {
+	for _, a := range b.accounts {
+		if a.ID == accountID {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (b *bank) accountByNumber(number sbanken.AccountNumber) *sbanken.Account {
+	for _, a := range b.accounts {
+		if number != "" && a.Number.String() == number.String() {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (b *bank) efaktura(efakturaID string) *sbanken.Efaktura {
+	for _, e := range b.efakturas {
+		if e.ID == efakturaID {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// book moves amount from one account to another and records the transactions.
+// A nil to account books the amount out of the bank, e.g. to the issuer of an efaktura.
+func (b *bank) book(from *sbanken.Account, to *sbanken.Account, toNumber sbanken.AccountNumber, amount sbanken.Money, text string, transactionType string) {
+	b.seq++
+	id := fmt.Sprintf("sbankentest-transaction-%d", b.seq)
+	now := time.Now()
+	today := sbanken.DateOf(now.Date())
+
+	from.Available = from.Available.Sub(amount)
+	from.Balance = from.Balance.Sub(amount)
+
+	b.transactions[from.ID] = append(b.transactions[from.ID], sbanken.Transaction{
+		TransactionID:               id,
+		AccountingDate:              today,
+		InterestDate:                today,
+		OtherAccountNumber:          toNumber,
+		OtherAccountNumberSpecified: toNumber != "",
+		Text:                        text,
+		TransactionType:             transactionType,
+		Source:                      "AccountStatement",
+		Amount:                      amount.Neg(),
+	})
+
+	if to == nil {
+		return
+	}
+
+	to.Available = to.Available.Add(amount)
+	to.Balance = to.Balance.Add(amount)
+
+	b.transactions[to.ID] = append(b.transactions[to.ID], sbanken.Transaction{
+		TransactionID:               id,
+		AccountingDate:              today,
+		InterestDate:                today,
+		OtherAccountNumber:          from.Number,
+		OtherAccountNumberSpecified: from.Number != "",
+		Text:                        text,
+		TransactionType:             transactionType,
+		Source:                      "AccountStatement",
+		Amount:                      amount,
+	})
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts := make([]sbanken.Account, len(s.bank.accounts))
+	for i, a := range s.bank.accounts {
+		accounts[i] = *a
+	}
+
+	writePage(s, w, r, accounts)
+}
+
+func (s *Server) readAccount(w http.ResponseWriter, r *http.Request, accountID string) {
+	a := s.bank.account(accountID)
+	if a == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	s.writeItem(w, a)
+}
+
+func (s *Server) getCustomer(w http.ResponseWriter, r *http.Request) {
+	if s.bank.customer == nil {
+		s.writeError(w, http.StatusNotFound, "NotFound", "customer not found")
+		return
+	}
+
+	s.writeItem(w, s.bank.customer)
+}
+
+func (s *Server) listEfakturas(w http.ResponseWriter, r *http.Request, onlyNew bool) {
+	start, end, err := dateParams(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Input", err.Error())
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if onlyNew {
+		status = EfakturaStatusNew
+	}
+
+	var efakturas []sbanken.Efaktura
+
+	for _, e := range s.bank.efakturas {
+		if status != "" && e.Status != status {
+			continue
+		}
+
+		if !inRange(e.OriginalDueDate.Time, start, end) {
+			continue
+		}
+
+		efakturas = append(efakturas, *e)
+	}
+
+	writePage(s, w, r, efakturas)
+}
+
+func (s *Server) readEfaktura(w http.ResponseWriter, r *http.Request, efakturaID string) {
+	e := s.bank.efaktura(efakturaID)
+	if e == nil {
+		s.writeError(w, http.StatusNotFound, "NotFound", "efaktura not found")
+		return
+	}
+
+	s.writeItem(w, e)
+}
+
+// payEfaktura pays an efaktura from an account. The amount is credited to the account
+// matching the credit account number of the efaktura, if any.
+func (s *Server) payEfaktura(w http.ResponseWriter, r *http.Request) {
+	var q sbanken.EfakturaPayQuery
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Input", err.Error())
+		return
+	}
+
+	e := s.bank.efaktura(q.ID)
+	if e == nil {
+		s.writeError(w, http.StatusNotFound, "NotFound", "efaktura not found")
+		return
+	}
+
+	from := s.bank.account(q.AccountID)
+	if from == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	if e.Status != EfakturaStatusNew {
+		s.writeError(w, http.StatusBadRequest, "Input", "efaktura is already processed")
+		return
+	}
+
+	// The bank rejects payments with a KID failing its check digit, like a real bank would.
+	if e.KID != "" && !e.KID.Valid() {
+		s.writeError(w, http.StatusBadRequest, "Input", "invalid KID")
+		return
+	}
+
+	amount := e.UpdatedAmount
+	if amount.IsZero() {
+		amount = e.OriginalAmount
+	}
+
+	if q.PayOnlyMinimumAmount {
+		amount = e.MinimumAmount
+	}
+
+	if from.Available.Cmp(amount) < 0 {
+		s.writeError(w, http.StatusBadRequest, "InsufficientFunds", "insufficient funds")
+		return
+	}
+
+	text := e.IssuerName
+	if e.KID != "" {
+		text = fmt.Sprintf("%s KID %s", e.IssuerName, e.KID)
+	}
+
+	s.bank.book(from, s.bank.accountByNumber(e.CreditAccountNumber), e.CreditAccountNumber, amount, text, "EFAKTURA")
+	e.Status = EfakturaStatusProcessed
+
+	s.write(w, http.StatusOK, apiResponse{})
+}
+
+func (s *Server) listPayments(w http.ResponseWriter, r *http.Request, accountID string) {
+	if s.bank.account(accountID) == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	writePage(s, w, r, s.bank.payments[accountID])
+}
+
+func (s *Server) readPayment(w http.ResponseWriter, r *http.Request, accountID string, paymentID string) {
+	if s.bank.account(accountID) == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	for _, p := range s.bank.payments[accountID] {
+		if p.ID == paymentID {
+			s.writeItem(w, p)
+			return
+		}
+	}
+
+	s.writeError(w, http.StatusNotFound, "NotFound", "payment not found")
+}
+
+func (s *Server) listStandingOrders(w http.ResponseWriter, r *http.Request, accountID string) {
+	if s.bank.account(accountID) == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	writePage(s, w, r, s.bank.standingOrders[accountID])
+}
+
+// listTransactions lists the transactions of an account within the startDate and endDate query
+// parameters. The archive only has booked transactions, leaving out reservations.
+func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request, accountID string, archive bool) {
+	if s.bank.account(accountID) == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	start, end, err := dateParams(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Input", err.Error())
+		return
+	}
+
+	var transactions []sbanken.Transaction
+
+	for _, t := range s.bank.transactions[accountID] {
+		if archive && t.IsReservation {
+			continue
+		}
+
+		if !inRange(t.AccountingDate.Time, start, end) {
+			continue
+		}
+
+		transactions = append(transactions, t)
+	}
+
+	writePage(s, w, r, transactions)
+}
+
+func (s *Server) transfer(w http.ResponseWriter, r *http.Request) {
+	var q sbanken.TransferQuery
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Input", err.Error())
+		return
+	}
+
+	from := s.bank.account(q.FromAccountID)
+	to := s.bank.account(q.ToAccountID)
+
+	if from == nil || to == nil {
+		s.writeError(w, http.StatusNotFound, "AccountNotFound", "account not found")
+		return
+	}
+
+	if from == to {
+		s.writeError(w, http.StatusBadRequest, "Input", "cannot transfer to the same account")
+		return
+	}
+
+	if q.Amount.Cmp(0) <= 0 {
+		s.writeError(w, http.StatusBadRequest, "Input", "amount must be positive")
+		return
+	}
+
+	if from.Available.Cmp(q.Amount) < 0 {
+		s.writeError(w, http.StatusBadRequest, "InsufficientFunds", "insufficient funds")
+		return
+	}
+
+	s.bank.book(from, to, to.Number, q.Amount, q.Message, "OVFNETTB")
+
+	s.write(w, http.StatusOK, apiResponse{})
+}
+
+// dateParams returns the startDate and endDate query parameters. Missing dates are zero.
+func dateParams(r *http.Request) (time.Time, time.Time, error) {
+	var dates [2]time.Time
+
+	for i, key := range []string{"startDate", "endDate"} {
+		v := r.URL.Query().Get(key)
+		if v == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s: %q", key, v)
+		}
+
+		dates[i] = t
+	}
+
+	return dates[0], dates[1], nil
+}
+
+func inRange(t time.Time, start time.Time, end time.Time) bool {
+	if !start.IsZero() && t.Before(start) {
+		return false
+	}
+
+	if !end.IsZero() && t.After(end) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,563 @@
+package sbankentest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of an existing cassette without using the network.
+	ModeReplay Mode = iota
+	// ModeRecord performs the requests and records the interactions to a new cassette.
+	ModeRecord
+)
+
+// MatchMode is how a Recorder matches requests to recorded interactions when replaying.
+type MatchMode int
+
+const (
+	// MatchStrict requires the requests to come in the recorded order, with the same
+	// method, URL and body as the recorded requests.
+	MatchStrict MatchMode = iota
+	// MatchLenient matches requests on method and URL path in any order, ignoring the
+	// query and body. Unused interactions are matched first, and the last matching
+	// interaction is reused when all are used.
+	MatchLenient
+)
+
+// Redacted replaces tokens and customer data in cassettes.
+const Redacted = "REDACTED"
+
+// ErrNoMatch are returned when replaying a request without a matching recorded interaction.
+var ErrNoMatch = errors.New("no recorded interaction")
+
+// NoMatchError describes a request without a matching recorded interaction.
+// It matches ErrNoMatch with errors.Is.
+type NoMatchError struct {
+	// Request is the method and URL of the request.
+	Request string
+	// Expected is the method and URL of the next recorded interaction when matching strictly, if any.
+	Expected string
+	// Cassette is the path of the cassette.
+	Cassette string
+}
+
+// Error returns the string representation of the error.
+func (e *NoMatchError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("sbankentest: %s in %s for %s, expected %s", ErrNoMatch, e.Cassette, e.Request, e.Expected)
+	}
+
+	return fmt.Sprintf("sbankentest: %s in %s for %s", ErrNoMatch, e.Cassette, e.Request)
+}
+
+// Is reports whether target is ErrNoMatch.
+func (e *NoMatchError) Is(target error) bool {
+	return target == ErrNoMatch
+}
+
+// Interaction is a recorded request and response, stored as one line of a cassette.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request. Headers are not recorded, to leave out credentials.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	StatusCode int         `json:"statusCode"`
+}
+
+// Recorder is an http.RoundTripper recording interactions to a JSONL cassette, or replaying them.
+//
+// Access tokens, customer data, names, free text such as transaction texts and messages, and KIDs
+// are redacted from the recorded bodies, and the digits of account and card numbers are zeroed.
+// Account, payment, efaktura, standing order and card IDs are replaced by pseudonyms in both URLs
+// and bodies, so that recorded sessions still match when replayed. Other fields, such as amounts,
+// dates and transaction types, are kept, so review cassettes before committing them. Use the Client of the Recorder as the HTTP client of sbanken.NewClient:
+//
+//	rec, err := sbankentest.NewRecorder("testdata/accounts.jsonl", sbankentest.ModeReplay, sbankentest.MatchStrict)
+//	...
+//	c, err := sbanken.NewClient(ctx, cfg, rec.Client())
+type Recorder struct {
+	// Transport performs the requests when recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mu           sync.Mutex
+	path         string
+	mode         Mode
+	match        MatchMode
+	file         *os.File
+	interactions []Interaction
+	used         []bool
+	next         int
+}
+
+// NewRecorder returns a Recorder for the cassette at path. When recording, the cassette is
+// created or truncated, and the caller should call Close when finished. When replaying,
+// the cassette must exist.
+func NewRecorder(path string, mode Mode, match MatchMode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, match: match}
+
+	if mode == ModeRecord {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+
+		r.file = f
+
+		return r, nil
+	}
+
+	interactions, err := readCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.interactions = interactions
+	r.used = make([]bool, len(interactions))
+
+	return r, nil
+}
+
+func readCassette(path string) ([]Interaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var interactions []Interaction
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+
+		interactions = append(interactions, i)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return interactions, nil
+}
+
+// Client returns an HTTP client using the Recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Close closes the cassette when recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+// RoundTrip records or replays a request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    redactURL(req.URL),
+		Body:   string(redactBody(body)),
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	res := r.interactions[i].Response
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        res.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := res.Header.Clone()
+	header.Del("Date")
+	header.Del("Set-Cookie")
+
+	line, err := json.Marshal(Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Header:     header,
+			Body:       string(redactBody(body)),
+			StatusCode: res.StatusCode,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil, fmt.Errorf("sbankentest: cassette %s is closed", r.path)
+	}
+
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// find returns the index of the interaction matching the request, and marks it as used.
+func (r *Recorder) find(req RecordedRequest) (int, error) {
+	noMatch := &NoMatchError{Request: req.Method + " " + req.URL, Cassette: r.path}
+
+	if r.match == MatchStrict {
+		if r.next >= len(r.interactions) {
+			return 0, noMatch
+		}
+
+		exp := r.interactions[r.next].Request
+		if exp.Method != req.Method || exp.URL != req.URL || exp.Body != req.Body {
+			noMatch.Expected = exp.Method + " " + exp.URL
+			return 0, noMatch
+		}
+
+		r.used[r.next] = true
+		r.next++
+
+		return r.next - 1, nil
+	}
+
+	last := -1
+
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != req.Method || path(interaction.Request.URL) != path(req.URL) {
+			continue
+		}
+
+		if !r.used[i] {
+			r.used[i] = true
+			return i, nil
+		}
+
+		last = i
+	}
+
+	if last < 0 {
+		return 0, noMatch
+	}
+
+	return last, nil
+}
+
+// path returns the path of a URL, leaving out the scheme, host and query.
+func path(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+
+		if j := strings.Index(u, "/"); j >= 0 {
+			u = u[j:]
+		}
+	}
+
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+
+	return u
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// redactedFields are the JSON fields holding tokens, customer data, names and free text.
+// Their values are replaced by Redacted.
+var redactedFields = map[string]bool{
+	"access_token":    true,
+	"refresh_token":   true,
+	"id_token":        true,
+	"customerid":      true,
+	"ownercustomerid": true,
+	"firstname":       true,
+	"lastname":        true,
+	"emailaddress":    true,
+	"phonenumbers":    true,
+	"postaladdress":   true,
+	"streetaddress":   true,
+	"accountowner":    true,
+	"payername":       true,
+	"receivername":    true,
+	"beneficiaryname": true,
+	"merchantname":    true,
+	"merchantcity":    true,
+	"name":            true,
+	"text":            true,
+	"message":         true,
+}
+
+// redactedDates are the JSON fields holding customer dates. Their values are replaced by redactedDate.
+var redactedDates = map[string]bool{
+	"dateofbirth": true,
+}
+
+// redactedDate replaces customer dates in cassettes, as a value that still decodes as a date.
+const redactedDate = "1900-01-01T00:00:00"
+
+// digitFields are the JSON fields holding account and card numbers and payment references.
+// Their digits are replaced by zeros, keeping the length and formatting.
+var digitFields = map[string]bool{
+	"accountnumber":          true,
+	"otheraccountnumber":     true,
+	"recipientaccountnumber": true,
+	"creditaccountnumber":    true,
+	"debitaccountnumber":     true,
+	"formattedaccountnumber": true,
+	"cardnumber":             true,
+	"kid":                    true,
+	"cid":                    true,
+}
+
+// idFields are the JSON fields holding IDs that also appear in URLs. Their values are replaced by pseudonyms.
+var idFields = map[string]bool{
+	"accountid":       true,
+	"fromaccountid":   true,
+	"toaccountid":     true,
+	"paymentid":       true,
+	"efakturaid":      true,
+	"standingorderid": true,
+	"cardid":          true,
+}
+
+// idCollections are the URL path segments followed by IDs, such as /Accounts/{accountId}.
+// Payments are followed by both an account and a payment ID.
+var idCollections = map[string]int{
+	"Accounts":       1,
+	"Efakturas":      1,
+	"Payments":       2,
+	"StandingOrders": 1,
+	"Transactions":   1,
+	"archive":        1,
+}
+
+// pseudonymPrefix is the prefix of the pseudonyms replacing IDs.
+const pseudonymPrefix = "redacted-"
+
+// pseudonym returns a stable pseudonym for an ID, so that the same ID gets the same pseudonym
+// in every URL and body. Pseudonyms are returned unchanged, as replayed requests use them.
+func pseudonym(id string) string {
+	if id == "" || strings.HasPrefix(id, pseudonymPrefix) {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(id))
+
+	return pseudonymPrefix + hex.EncodeToString(sum[:8])
+}
+
+// redactURL returns the URL with the IDs in its path replaced by pseudonyms.
+func redactURL(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+
+	for i := 0; i < len(segments); i++ {
+		n := idCollections[segments[i]]
+
+		for j := i + 1; j <= i+n && j < len(segments); j++ {
+			// Collections such as /Efakturas/new and /Transactions/archive/{accountId} continue the path.
+			if _, ok := idCollections[segments[j]]; ok || segments[j] == "new" {
+				break
+			}
+
+			segments[j] = pseudonym(segments[j])
+			i = j
+		}
+	}
+
+	redacted := *u
+	redacted.Path = strings.Join(segments, "/")
+	redacted.RawPath = ""
+
+	return redacted.String()
+}
+
+// redactBody redacts a JSON body. Bodies that are not JSON are returned unchanged.
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return body
+	}
+
+	redacted, err := json.Marshal(redact(v))
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			switch key := strings.ToLower(k); {
+			case redactedFields[key]:
+				v[k] = redactValue(val)
+			case redactedDates[key]:
+				if s, ok := val.(string); ok && s != "" {
+					v[k] = redactedDate
+				}
+			case digitFields[key]:
+				if s, ok := val.(string); ok {
+					v[k] = zeroDigits(s)
+				}
+			case idFields[key]:
+				if s, ok := val.(string); ok {
+					v[k] = pseudonym(s)
+				}
+			default:
+				v[k] = redact(val)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+
+	return v
+}
+
+// redactValue replaces all strings in a value by Redacted, keeping its structure.
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+
+		return Redacted
+	case map[string]interface{}:
+		for k, val := range v {
+			v[k] = redactValue(val)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+
+	return v
+}
+
+func zeroDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '0'
+		}
+
+		return r
+	}, s)
+}
@@ -0,0 +1,236 @@
+package sbankentest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// recordTestCassette records a cassette of a client session against a Server.
+func recordTestCassette(t *testing.T) (string, *sbanken.Config) {
+	t.Helper()
+
+	ctx := context.Background()
+	srv, _ := newTestServer(t)
+
+	srv.SetCustomer(sbanken.Customer{
+		CustomerID:    "12345678901",
+		FirstName:     "Kari",
+		LastName:      "Nordmann",
+		EmailAddress:  "kari@example.com",
+		DateOfBirth:   sbanken.DateOf(1980, 1, 2),
+		PostalAddress: sbanken.Address{AddressLine1: "Storgata 1", City: "Oslo"},
+		PhoneNumbers:  []sbanken.PhoneNumber{{CountryCode: "47", Number: "99999999"}},
+	})
+
+	checking, _ := srv.Account("checking")
+	checking.Name = "Karis brukskonto"
+	srv.AddAccounts(checking)
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	rec, err := NewRecorder(path, ModeRecord, MatchStrict)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	cfg := srv.Config()
+
+	c, err := sbanken.NewClient(ctx, cfg, rec.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if _, err := c.ListAccounts(ctx); err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if _, err := c.GetCustomer(ctx); err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if err := c.Transfer(ctx, &sbanken.TransferQuery{FromAccountID: "checking", ToAccountID: "savings", Amount: 100, Message: "Husleie til Ola Nordmann"}); err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if _, err := c.ListTransactions(ctx, "checking", nil); err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	srv.Close()
+
+	return path, cfg
+}
+
+func TestRecorderRedaction(t *testing.T) {
+	path, _ := recordTestCassette(t)
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cassette := string(b)
+
+	if n := strings.Count(cassette, "\n"); n != 5 {
+		t.Errorf("unexpected number of interactions: got %d, exp 5", n)
+	}
+
+	for _, secret := range []string{AccessToken, ClientSecret, "12345678901", "Kari", "Nordmann", "kari@example.com", "1980", "Storgata", "99999999", "97104133219", "97101234566", "Husleie", "brukskonto", "checking", "savings"} {
+		if strings.Contains(cassette, secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	if !strings.Contains(cassette, `\"accountNumber\":\"00000000000\"`) {
+		t.Errorf("account numbers not zeroed: %s", cassette)
+	}
+
+	if !strings.Contains(cassette, `\"text\":\"REDACTED\"`) {
+		t.Errorf("transaction text not redacted: %s", cassette)
+	}
+
+	if !strings.Contains(cassette, "/Transactions/"+pseudonym("checking")) {
+		t.Errorf("account ID in URL not replaced: %s", cassette)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{name: "should keep collections", in: "https://example.com/api/v1/Accounts?index=0", exp: "https://example.com/api/v1/Accounts?index=0"},
+		{name: "should replace account ID", in: "https://example.com/api/v1/Accounts/account", exp: "https://example.com/api/v1/Accounts/" + pseudonym("account")},
+		{name: "should replace account and payment ID", in: "/api/v1/Payments/account/payment", exp: "/api/v1/Payments/" + pseudonym("account") + "/" + pseudonym("payment")},
+		{name: "should replace archived transactions account ID", in: "/api/v1/Transactions/archive/account", exp: "/api/v1/Transactions/archive/" + pseudonym("account")},
+		{name: "should keep new efakturas", in: "/api/v1/Efakturas/new", exp: "/api/v1/Efakturas/new"},
+		{name: "should keep pseudonyms", in: "/api/v1/Accounts/" + pseudonym("account"), exp: "/api/v1/Accounts/" + pseudonym("account")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.in)
+			if err != nil {
+				t.Fatalf("error setting up test: %v", err)
+			}
+
+			if got := redactURL(u); got != tc.exp {
+				t.Errorf("unexpected URL: got %s, exp %s", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{name: "should redact transaction text", in: `{"text":"Ola Nordmann"}`, exp: `{"text":"REDACTED"}`},
+		{name: "should redact account name", in: `{"name":"Karis konto"}`, exp: `{"name":"REDACTED"}`},
+		{name: "should zero KID digits", in: `{"kid":"1234567-"}`, exp: `{"kid":"0000000-"}`},
+		{name: "should zero CID digits", in: `{"cId":"12345674"}`, exp: `{"cId":"00000000"}`},
+		{name: "should replace account ID", in: `{"accountId":"account"}`, exp: `{"accountId":"` + pseudonym("account") + `"}`},
+		{name: "should keep other fields", in: `{"amount":-100.5,"transactionType":"VARER"}`, exp: `{"amount":-100.5,"transactionType":"VARER"}`},
+		{name: "should keep non JSON body", in: `grant_type=client_credentials`, exp: `grant_type=client_credentials`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(redactBody([]byte(tc.in))); got != tc.exp {
+				t.Errorf("unexpected body: got %s, exp %s", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestRecorderReplay(t *testing.T) {
+	ctx := context.Background()
+	path, cfg := recordTestCassette(t)
+
+	tests := []struct {
+		name   string
+		match  MatchMode
+		calls  []string
+		expErr error
+	}{
+		{name: "should replay in recorded order", match: MatchStrict, calls: []string{"accounts", "customer", "transfer", "transactions"}},
+		{name: "should fail out of order when strict", match: MatchStrict, calls: []string{"customer"}, expErr: ErrNoMatch},
+		{name: "should fail after the last interaction when strict", match: MatchStrict, calls: []string{"accounts", "customer", "transfer", "transactions", "accounts"}, expErr: ErrNoMatch},
+		{name: "should replay in any order when lenient", match: MatchLenient, calls: []string{"transactions", "transfer", "customer", "accounts", "accounts"}},
+		{name: "should fail unrecorded request when lenient", match: MatchLenient, calls: []string{"cards"}, expErr: ErrNoMatch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, err := NewRecorder(path, ModeReplay, tc.match)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			c, err := sbanken.NewClient(ctx, cfg, rec.Client())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, call := range tc.calls {
+				switch call {
+				case "accounts":
+					var accounts []sbanken.Account
+					accounts, err = c.ListAccounts(ctx)
+					if err == nil && len(accounts) != 3 {
+						t.Errorf("unexpected accounts: %v", accounts)
+					}
+				case "customer":
+					var customer sbanken.Customer
+					customer, err = c.GetCustomer(ctx)
+					if err == nil && customer.FirstName != Redacted {
+						t.Errorf("unexpected customer: %v", customer)
+					}
+				case "transfer":
+					err = c.Transfer(ctx, &sbanken.TransferQuery{FromAccountID: "checking", ToAccountID: "savings", Amount: 100, Message: "Husleie til Ola Nordmann"})
+				case "transactions":
+					var transactions []sbanken.Transaction
+					transactions, err = c.ListTransactions(ctx, "checking", nil)
+					if err == nil && (len(transactions) != 1 || transactions[0].Text != Redacted) {
+						t.Errorf("unexpected transactions: %v", transactions)
+					}
+				case "cards":
+					_, err = c.ListCards(ctx)
+				}
+
+				if err != nil {
+					break
+				}
+			}
+
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			var noMatch *NoMatchError
+			if tc.expErr != nil && (!errors.As(err, &noMatch) || noMatch.Cassette != path) {
+				t.Errorf("expected NoMatchError: got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewRecorderMissingCassette(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.jsonl"), ModeReplay, MatchStrict); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,246 @@
+package sbankentest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/engvik/sbanken-go"
+)
+
+// Fault is a failure injected by a FaultInjector.
+type Fault int
+
+const (
+	// FaultNone passes the request through unchanged.
+	FaultNone Fault = iota
+	// FaultLatency delays the request by the Latency of the injector.
+	FaultLatency
+	// FaultServerError responds with the ServerErrorStatusCode of the injector without performing the request.
+	FaultServerError
+	// FaultRateLimited responds with 429 Too Many Requests and a Retry-After header without performing the request.
+	FaultRateLimited
+	// FaultTruncatedBody performs the request and cuts the response body in half.
+	FaultTruncatedBody
+	// FaultMalformedJSON performs the request and replaces the response body with malformed JSON.
+	FaultMalformedJSON
+	// FaultTokenExpired responds with 401 Unauthorized as if the access token expired, without performing the request.
+	// The client discards its token and sends idempotent requests once more with a new one, so one
+	// FaultTokenExpired is recovered from, while two in a row fail with ErrUnauthorized.
+	FaultTokenExpired
+	// FaultErrorEnvelope responds with 200 OK and an isError envelope without performing the request.
+	FaultErrorEnvelope
+)
+
+// faults are the faults injected randomly when no faults are set.
+var faults = []Fault{
+	FaultLatency,
+	FaultServerError,
+	FaultRateLimited,
+	FaultTruncatedBody,
+	FaultMalformedJSON,
+	FaultTokenExpired,
+	FaultErrorEnvelope,
+}
+
+// String returns the name of the fault.
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "None"
+	case FaultLatency:
+		return "Latency"
+	case FaultServerError:
+		return "ServerError"
+	case FaultRateLimited:
+		return "RateLimited"
+	case FaultTruncatedBody:
+		return "TruncatedBody"
+	case FaultMalformedJSON:
+		return "MalformedJSON"
+	case FaultTokenExpired:
+		return "TokenExpired"
+	case FaultErrorEnvelope:
+		return "ErrorEnvelope"
+	}
+
+	return fmt.Sprintf("Fault(%d)", int(f))
+}
+
+// FaultInjector injects faults into the requests of a client, for testing how applications
+// behave when the API misbehaves. Add its Middleware to the client configuration.
+//
+// Faults are injected per attempt, so retries see them too. Scripted faults are injected
+// first, in order, for the operation they are scripted for, such as "ListAccounts".
+// Other attempts get a random fault with probability Rate, from a source seeded by
+// NewFaultInjector, so that runs are reproducible. The zero value is ready to use,
+// with a source seeded with 1.
+//
+// The fields should not be changed after the middleware is in use.
+type FaultInjector struct {
+	// Rate is the probability of injecting a random fault into an attempt. Defaults to zero.
+	Rate float64
+	// Faults are the faults to choose from when injecting randomly. Defaults to all faults.
+	Faults []Fault
+	// Latency is the delay of FaultLatency. Defaults to one second.
+	Latency time.Duration
+	// RetryAfter is the Retry-After of FaultRateLimited. Defaults to one second.
+	RetryAfter time.Duration
+	// ServerErrorStatusCode is the status code of FaultServerError. Defaults to 503 Service Unavailable.
+	ServerErrorStatusCode int
+	// OnInject is optionally called with the operation and fault of every injected fault.
+	OnInject func(operation string, f Fault)
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	scripts map[string][]Fault
+}
+
+// NewFaultInjector returns a FaultInjector drawing random faults from a source with the given seed.
+func NewFaultInjector(seed int64) *FaultInjector {
+	return &FaultInjector{
+		rand:    rand.New(rand.NewSource(seed)),
+		scripts: map[string][]Fault{},
+	}
+}
+
+// Script appends faults to inject, in order, into the next attempts of the operation.
+// FaultNone lets an attempt through, e.g. to fail only the second attempt.
+func (f *FaultInjector) Script(operation string, faults ...Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.scripts == nil {
+		f.scripts = map[string][]Fault{}
+	}
+
+	f.scripts[operation] = append(f.scripts[operation], faults...)
+}
+
+// next returns the fault to inject into the next attempt of the operation.
+func (f *FaultInjector) next(operation string) Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if script := f.scripts[operation]; len(script) > 0 {
+		f.scripts[operation] = script[1:]
+		return script[0]
+	}
+
+	if f.Rate <= 0 {
+		return FaultNone
+	}
+
+	if f.rand == nil {
+		f.rand = rand.New(rand.NewSource(1))
+	}
+
+	if f.rand.Float64() >= f.Rate {
+		return FaultNone
+	}
+
+	choices := f.Faults
+	if len(choices) == 0 {
+		choices = faults
+	}
+
+	return choices[f.rand.Intn(len(choices))]
+}
+
+// Middleware returns the middleware injecting the faults.
+func (f *FaultInjector) Middleware() sbanken.Middleware {
+	return func(next sbanken.Handler) sbanken.Handler {
+		return func(ctx context.Context, r *sbanken.HTTPRequest) (*sbanken.Response, error) {
+			fault := f.next(r.Operation)
+			if fault == FaultNone {
+				return next(ctx, r)
+			}
+
+			if f.OnInject != nil {
+				f.OnInject(r.Operation, fault)
+			}
+
+			return f.inject(ctx, fault, next, r)
+		}
+	}
+}
+
+func (f *FaultInjector) inject(ctx context.Context, fault Fault, next sbanken.Handler, r *sbanken.HTTPRequest) (*sbanken.Response, error) {
+	switch fault {
+	case FaultLatency:
+		latency := f.Latency
+		if latency == 0 {
+			latency = time.Second
+		}
+
+		t := time.NewTimer(latency)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		return next(ctx, r)
+	case FaultServerError:
+		sc := f.ServerErrorStatusCode
+		if sc == 0 {
+			sc = http.StatusServiceUnavailable
+		}
+
+		return jsonResponse(sc, nil, `{"isError":true,"errorType":"ServiceUnavailable","errorMessage":"injected server error"}`), nil
+	case FaultRateLimited:
+		retryAfter := f.RetryAfter
+		if retryAfter == 0 {
+			retryAfter = time.Second
+		}
+
+		header := http.Header{"Retry-After": {strconv.Itoa(int(retryAfter.Round(time.Second) / time.Second))}}
+
+		return jsonResponse(http.StatusTooManyRequests, header, ""), nil
+	case FaultTruncatedBody, FaultMalformedJSON:
+		// The body is buffered rather than streamed, so that it can be modified.
+		r.Stream = nil
+
+		res, err := next(ctx, r)
+		if res == nil {
+			return res, err
+		}
+
+		if fault == FaultTruncatedBody {
+			res.Body = res.Body[:len(res.Body)/2]
+		} else {
+			res.Body = []byte(`{"items":[{"accountId":}]`)
+		}
+
+		return res, err
+	case FaultTokenExpired:
+		header := http.Header{"WWW-Authenticate": {`Bearer error="invalid_token", error_description="The token expired"`}}
+
+		return jsonResponse(http.StatusUnauthorized, header, ""), nil
+	case FaultErrorEnvelope:
+		return jsonResponse(http.StatusOK, nil, `{"isError":true,"errorType":"System","errorMessage":"injected error"}`), nil
+	}
+
+	return next(ctx, r)
+}
+
+func jsonResponse(sc int, header http.Header, body string) *sbanken.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	header.Set("Content-Type", "application/json")
+
+	res := &sbanken.Response{Header: header, StatusCode: sc}
+	if body != "" {
+		res.Body = []byte(body)
+	}
+
+	return res
+}
@@ -0,0 +1,245 @@
+package sbankentest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/engvik/sbanken-go"
+)
+
+func newTestFaultClient(t *testing.T, f *FaultInjector, policy *sbanken.RetryPolicy) *sbanken.Client {
+	t.Helper()
+
+	srv, _ := newTestServer(t)
+	srv.AddTransactions("checking", sbanken.Transaction{TransactionID: "transaction"})
+
+	cfg := srv.Config()
+	cfg.Middleware = []sbanken.Middleware{f.Middleware()}
+	cfg.RetryPolicy = policy
+
+	c, err := sbanken.NewClient(context.Background(), cfg, srv.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	return c
+}
+
+func TestFaultInjector(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults []Fault
+		expErr error
+	}{
+		{name: "should pass through", faults: []Fault{FaultNone}},
+		{name: "should inject server error", faults: []Fault{FaultServerError}, expErr: sbanken.ErrServiceUnavailable},
+		{name: "should inject rate limiting", faults: []Fault{FaultRateLimited}, expErr: sbanken.ErrRateLimited},
+		{name: "should recover from token expiry", faults: []Fault{FaultTokenExpired}},
+		{name: "should fail when token is rejected twice", faults: []Fault{FaultTokenExpired, FaultTokenExpired}, expErr: sbanken.ErrUnauthorized},
+		{name: "should inject error envelope", faults: []Fault{FaultErrorEnvelope}, expErr: sbanken.ErrInternal},
+		{name: "should recover when retried", faults: []Fault{FaultServerError, FaultRateLimited, FaultNone}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var injected []Fault
+
+			f := NewFaultInjector(1)
+			f.RetryAfter = time.Millisecond
+			f.OnInject = func(operation string, fault Fault) {
+				injected = append(injected, fault)
+			}
+			f.Script("ListAccounts", tc.faults...)
+
+			var policy *sbanken.RetryPolicy
+			if len(tc.faults) > 1 {
+				policy = &sbanken.RetryPolicy{MaxAttempts: len(tc.faults), BaseDelay: time.Millisecond}
+			}
+
+			c := newTestFaultClient(t, f, policy)
+
+			accounts, err := c.ListAccounts(context.Background())
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if tc.expErr == nil && len(accounts) != 3 {
+				t.Errorf("unexpected accounts: %v", accounts)
+			}
+
+			var expInjected []Fault
+			for _, fault := range tc.faults {
+				if fault != FaultNone {
+					expInjected = append(expInjected, fault)
+				}
+			}
+
+			if !reflect.DeepEqual(injected, expInjected) {
+				t.Errorf("unexpected injected faults: got %v, exp %v", injected, expInjected)
+			}
+		})
+	}
+}
+
+// countingTokenSource counts the tokens fetched by the client.
+type countingTokenSource struct {
+	sbanken.TokenSource
+	calls int
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (*sbanken.Token, error) {
+	s.calls++
+	return s.TokenSource.Token(ctx)
+}
+
+func TestFaultInjectorTokenExpired(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	f := NewFaultInjector(1)
+	f.Script("ListAccounts", FaultTokenExpired)
+
+	cfg := srv.Config()
+	src := &countingTokenSource{TokenSource: sbanken.NewClientCredentialsTokenSource(cfg.ClientID, cfg.ClientSecret, cfg.AuthURL, srv.Client())}
+	cfg.TokenSource = src
+	cfg.Middleware = []sbanken.Middleware{f.Middleware()}
+
+	c, err := sbanken.NewClient(ctx, cfg, srv.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	if _, err := c.ListAccounts(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if src.calls != 2 {
+		t.Errorf("unexpected number of token requests: got %d, exp 2", src.calls)
+	}
+}
+
+func TestFaultInjectorZeroValue(t *testing.T) {
+	var f FaultInjector
+	f.Rate = 1
+	f.Script("ListAccounts", FaultNone)
+
+	if fault := f.next("ListAccounts"); fault != FaultNone {
+		t.Errorf("unexpected fault: got %v, exp %v", fault, FaultNone)
+	}
+
+	if fault := f.next("ListAccounts"); fault == FaultNone {
+		t.Error("expected random fault")
+	}
+}
+
+func TestFaultInjectorBody(t *testing.T) {
+	ctx := context.Background()
+
+	for _, fault := range []Fault{FaultTruncatedBody, FaultMalformedJSON} {
+		t.Run("should fail decoding with "+fault.String(), func(t *testing.T) {
+			f := NewFaultInjector(1)
+			f.Script("ListAccounts", fault)
+			f.Script("ListTransactions", fault)
+
+			c := newTestFaultClient(t, f, nil)
+
+			if _, err := c.ListAccounts(ctx); err == nil {
+				t.Error("expected error")
+			}
+
+			_, err := c.StreamTransactions(ctx, "checking", nil, func(sbanken.Transaction) error { return nil })
+			if err == nil {
+				t.Error("expected error when streaming")
+			}
+		})
+	}
+}
+
+func TestFaultInjectorLatency(t *testing.T) {
+	f := NewFaultInjector(1)
+	f.Latency = 50 * time.Millisecond
+	f.Script("ListAccounts", FaultLatency, FaultLatency)
+
+	c := newTestFaultClient(t, f, nil)
+
+	start := time.Now()
+	if _, err := c.ListAccounts(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d := time.Since(start); d < f.Latency {
+		t.Errorf("unexpected duration: got %s, exp at least %s", d, f.Latency)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.ListAccounts(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, exp %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFaultInjectorRandom(t *testing.T) {
+	draw := func(seed int64, rate float64) []Fault {
+		f := NewFaultInjector(seed)
+		f.Rate = rate
+
+		drawn := make([]Fault, 100)
+		for i := range drawn {
+			drawn[i] = f.next("ListAccounts")
+		}
+
+		return drawn
+	}
+
+	tests := []struct {
+		name  string
+		rate  float64
+		check func(t *testing.T, drawn []Fault)
+	}{
+		{
+			name: "should not inject with zero rate",
+			check: func(t *testing.T, drawn []Fault) {
+				for _, fault := range drawn {
+					if fault != FaultNone {
+						t.Fatalf("unexpected fault: %v", fault)
+					}
+				}
+			},
+		},
+		{
+			name: "should always inject with rate one",
+			rate: 1,
+			check: func(t *testing.T, drawn []Fault) {
+				for _, fault := range drawn {
+					if fault == FaultNone {
+						t.Fatal("expected fault")
+					}
+				}
+			},
+		},
+		{
+			name: "should be reproducible with the same seed",
+			rate: 0.3,
+			check: func(t *testing.T, drawn []Fault) {
+				if !reflect.DeepEqual(drawn, draw(42, 0.3)) {
+					t.Error("unexpected faults with same seed")
+				}
+
+				if reflect.DeepEqual(drawn, draw(43, 0.3)) {
+					t.Error("expected different faults with different seed")
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check(t, draw(42, tc.rate))
+		})
+	}
+}
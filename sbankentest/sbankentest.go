@@ -0,0 +1,215 @@
+// Package sbankentest provides an in-process fake of the Sbanken API for integration tests.
+//
+// The Server implements the identity server token endpoint and the v1 endpoints used by
+// the sbanken client, backed by an in-memory bank. Transfers and efaktura payments move
+// balances between the seeded accounts and are recorded as transactions:
+//
+//	srv := sbankentest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddAccounts(sbanken.Account{ID: "from", Available: 1000_00, Balance: 1000_00})
+//	srv.AddAccounts(sbanken.Account{ID: "to"})
+//
+//	c, err := sbanken.NewClient(ctx, srv.Config(), srv.Client())
+package sbankentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/engvik/sbanken-go"
+)
+
+const (
+	// ClientID is the client ID accepted by the Server.
+	ClientID = "sbankentest-client-id"
+	// ClientSecret is the client secret accepted by the Server.
+	ClientSecret = "sbankentest-client-secret"
+	// AccessToken is the access token issued by the Server.
+	AccessToken = "sbankentest-token"
+
+	authPath = "/identityserver/connect/token"
+	apiPath  = "/apibeta/api/v1/"
+)
+
+// Server is a fake Sbanken API server.
+type Server struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	bank bank
+	seq  int64
+}
+
+// NewServer starts and returns a new Server with an empty bank. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{bank: newBank()}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Config returns a client configuration for the server.
+func (s *Server) Config() *sbanken.Config {
+	return &sbanken.Config{
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		BaseURL:      s.URL + strings.TrimSuffix(apiPath, "/v1/"),
+		AuthURL:      s.URL + authPath,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == authPath {
+		s.token(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, apiPath) {
+		s.writeError(w, http.StatusNotFound, "NotFound", "unknown endpoint")
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+AccessToken {
+		s.writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid access token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPath), "/"), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "Accounts":
+		s.listAccounts(w, r)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "Accounts":
+		s.readAccount(w, r, path[1])
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "Cards":
+		writePage(s, w, r, s.bank.cards)
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "Customers":
+		s.getCustomer(w, r)
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "Efakturas":
+		s.listEfakturas(w, r, false)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "Efakturas" && path[1] == "new":
+		s.listEfakturas(w, r, true)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "Efakturas":
+		s.readEfaktura(w, r, path[1])
+	case r.Method == http.MethodPost && len(path) == 1 && path[0] == "Efakturas":
+		s.payEfaktura(w, r)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "Payments":
+		s.listPayments(w, r, path[1])
+	case r.Method == http.MethodGet && len(path) == 3 && path[0] == "Payments":
+		s.readPayment(w, r, path[1], path[2])
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "StandingOrders":
+		s.listStandingOrders(w, r, path[1])
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "Transactions":
+		s.listTransactions(w, r, path[1], false)
+	case r.Method == http.MethodGet && len(path) == 3 && path[0] == "Transactions" && path[1] == "archive":
+		s.listTransactions(w, r, path[2], true)
+	case r.Method == http.MethodPost && len(path) == 1 && path[0] == "Transfers":
+		s.transfer(w, r)
+	default:
+		s.writeError(w, http.StatusNotFound, "NotFound", "unknown endpoint")
+	}
+}
+
+// token implements the client credentials grant of the identity server.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+
+	if r.Method != http.MethodPost || id != ClientID || secret != ClientSecret {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":%q,"token_type":"Bearer","expires_in":3600}`, AccessToken)
+}
+
+// apiResponse is the envelope of the API responses.
+type apiResponse struct {
+	Item           interface{} `json:"item,omitempty"`
+	Items          interface{} `json:"items,omitempty"`
+	TraceID        string      `json:"traceId"`
+	ErrorType      string      `json:"errorType,omitempty"`
+	ErrorMessage   string      `json:"errorMessage,omitempty"`
+	AvailableItems int         `json:"availableItems"`
+	IsError        bool        `json:"isError"`
+}
+
+func (s *Server) traceID() string {
+	return fmt.Sprintf("sbankentest-trace-%d", atomic.AddInt64(&s.seq, 1))
+}
+
+func (s *Server) write(w http.ResponseWriter, sc int, res apiResponse) {
+	res.TraceID = s.traceID()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(sc)
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) writeItem(w http.ResponseWriter, item interface{}) {
+	s.write(w, http.StatusOK, apiResponse{Item: item})
+}
+
+func (s *Server) writeError(w http.ResponseWriter, sc int, errorType string, msg string) {
+	s.write(w, sc, apiResponse{IsError: true, ErrorType: errorType, ErrorMessage: msg})
+}
+
+// writePage writes the page of items given by the index and length query parameters.
+func writePage[T any](s *Server, w http.ResponseWriter, r *http.Request, items []T) {
+	index, length, err := pageParams(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Input", err.Error())
+		return
+	}
+
+	available := len(items)
+
+	if index > len(items) {
+		index = len(items)
+	}
+
+	items = items[index:]
+
+	if length >= 0 && length < len(items) {
+		items = items[:length]
+	}
+
+	s.write(w, http.StatusOK, apiResponse{Items: append([]T{}, items...), AvailableItems: available})
+}
+
+func pageParams(r *http.Request) (int, int, error) {
+	index, length := 0, -1
+
+	if v := r.URL.Query().Get("index"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			return 0, 0, fmt.Errorf("invalid index: %q", v)
+		}
+
+		index = i
+	}
+
+	if v := r.URL.Query().Get("length"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 0 {
+			return 0, 0, fmt.Errorf("invalid length: %q", v)
+		}
+
+		length = l
+	}
+
+	return index, length, nil
+}
@@ -0,0 +1,264 @@
+package sbankentest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/engvik/sbanken-go"
+)
+
+func newTestServer(t *testing.T) (*Server, *sbanken.Client) {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddAccounts(
+		sbanken.Account{ID: "checking", Number: "97104133219", Available: 1000_00, Balance: 1000_00},
+		sbanken.Account{ID: "savings", Number: "97101234566", Available: 50_00, Balance: 50_00},
+		sbanken.Account{ID: "power", Number: "12345678903"},
+	)
+	srv.AddEfakturas(
+		sbanken.Efaktura{ID: "bill", IssuerName: "Power Company", KID: "12344", CreditAccountNumber: "12345678903", OriginalAmount: 400_00, MinimumAmount: 100_00},
+		sbanken.Efaktura{ID: "big-bill", OriginalAmount: 5000_00},
+	)
+	srv.SetCustomer(sbanken.Customer{CustomerID: "customer", FirstName: "Test"})
+
+	c, err := sbanken.NewClient(context.Background(), srv.Config(), srv.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	return srv, c
+}
+
+func TestTransfer(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          *sbanken.TransferQuery
+		expErr     error
+		expBalance map[string]sbanken.Money
+	}{
+		{
+			name:       "should move balances",
+			q:          &sbanken.TransferQuery{FromAccountID: "checking", ToAccountID: "savings", Amount: 250_50, Message: "Savings"},
+			expBalance: map[string]sbanken.Money{"checking": 749_50, "savings": 300_50},
+		},
+		{
+			name:       "should fail with insufficient funds",
+			q:          &sbanken.TransferQuery{FromAccountID: "savings", ToAccountID: "checking", Amount: 50_01},
+			expErr:     sbanken.ErrInsufficientFunds,
+			expBalance: map[string]sbanken.Money{"checking": 1000_00, "savings": 50_00},
+		},
+		{
+			name:       "should fail with unknown account",
+			q:          &sbanken.TransferQuery{FromAccountID: "checking", ToAccountID: "unknown", Amount: 1},
+			expErr:     sbanken.ErrAccountNotFound,
+			expBalance: map[string]sbanken.Money{"checking": 1000_00},
+		},
+		{
+			name:       "should fail with non positive amount",
+			q:          &sbanken.TransferQuery{FromAccountID: "checking", ToAccountID: "savings", Amount: -1},
+			expErr:     sbanken.ErrInvalidInput,
+			expBalance: map[string]sbanken.Money{"checking": 1000_00, "savings": 50_00},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, c := newTestServer(t)
+
+			err := c.Transfer(ctx, tc.q)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			for id, exp := range tc.expBalance {
+				a, err := c.ReadAccount(ctx, id)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if a.Balance != exp || a.Available != exp {
+					t.Errorf("unexpected balance of %s: got %v / %v, exp %v", id, a.Balance, a.Available, exp)
+				}
+			}
+
+			if tc.expErr != nil {
+				return
+			}
+
+			transactions, err := c.ListTransactions(ctx, "savings", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(transactions) != 1 || transactions[0].Amount != tc.q.Amount || transactions[0].Text != tc.q.Message {
+				t.Errorf("unexpected transactions: got %+v", transactions)
+			}
+
+			if got := srv.Transactions("checking"); len(got) != 1 || got[0].Amount != tc.q.Amount.Neg() {
+				t.Errorf("unexpected transactions: got %+v", got)
+			}
+		})
+	}
+}
+
+func TestPayEfaktura(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          *sbanken.EfakturaPayQuery
+		expErr     error
+		expBalance map[string]sbanken.Money
+		expStatus  string
+	}{
+		{
+			name:       "should pay efaktura",
+			q:          &sbanken.EfakturaPayQuery{ID: "bill", AccountID: "checking"},
+			expBalance: map[string]sbanken.Money{"checking": 600_00, "power": 400_00},
+			expStatus:  EfakturaStatusProcessed,
+		},
+		{
+			name:       "should pay minimum amount",
+			q:          &sbanken.EfakturaPayQuery{ID: "bill", AccountID: "checking", PayOnlyMinimumAmount: true},
+			expBalance: map[string]sbanken.Money{"checking": 900_00, "power": 100_00},
+			expStatus:  EfakturaStatusProcessed,
+		},
+		{
+			name:       "should fail with insufficient funds",
+			q:          &sbanken.EfakturaPayQuery{ID: "big-bill", AccountID: "checking"},
+			expErr:     sbanken.ErrInsufficientFunds,
+			expBalance: map[string]sbanken.Money{"checking": 1000_00},
+			expStatus:  EfakturaStatusNew,
+		},
+		{
+			name:       "should fail with invalid KID",
+			q:          &sbanken.EfakturaPayQuery{ID: "invalid-kid", AccountID: "checking"},
+			expErr:     sbanken.ErrInvalidInput,
+			expBalance: map[string]sbanken.Money{"checking": 1000_00, "power": 0},
+			expStatus:  EfakturaStatusNew,
+		},
+		{
+			name:   "should fail with unknown efaktura",
+			q:      &sbanken.EfakturaPayQuery{ID: "unknown", AccountID: "checking"},
+			expErr: sbanken.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			srv, c := newTestServer(t)
+			srv.AddEfakturas(sbanken.Efaktura{ID: "invalid-kid", KID: "1234", CreditAccountNumber: "12345678903", OriginalAmount: 100_00})
+
+			err := c.PayEfaktura(ctx, tc.q)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			for id, exp := range tc.expBalance {
+				if a, _ := srv.Account(id); a.Balance != exp {
+					t.Errorf("unexpected balance of %s: got %v, exp %v", id, a.Balance, exp)
+				}
+			}
+
+			if tc.expStatus == "" {
+				return
+			}
+
+			e, err := c.ReadEfaktura(ctx, tc.q.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if e.Status != tc.expStatus {
+				t.Errorf("unexpected status: got %q, exp %q", e.Status, tc.expStatus)
+			}
+		})
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	ctx := context.Background()
+	srv, c := newTestServer(t)
+
+	srv.AddPayments("checking", sbanken.Payment{ID: "payment"})
+	srv.AddStandingOrders("checking", sbanken.StandingOrder{StandingOrderID: 1})
+	srv.AddCards(sbanken.Card{ID: "card"})
+
+	for i := 0; i < 5; i++ {
+		srv.AddTransactions("checking", sbanken.Transaction{Amount: sbanken.Money(i), IsReservation: i == 0})
+	}
+
+	t.Run("should list accounts", func(t *testing.T) {
+		if accounts, err := c.ListAccounts(ctx); err != nil || len(accounts) != 3 {
+			t.Errorf("unexpected result: %v, %v", accounts, err)
+		}
+	})
+
+	t.Run("should page transactions", func(t *testing.T) {
+		transactions, err := c.ListAllTransactions(ctx, "checking", nil, &sbanken.PageOptions{PageSize: 2})
+		if err != nil || len(transactions) != 5 || transactions[4].Amount != 4 {
+			t.Errorf("unexpected result: %v, %v", transactions, err)
+		}
+	})
+
+	t.Run("should leave reservations out of the archive", func(t *testing.T) {
+		if transactions, err := c.ListArchivedTransactions(ctx, "checking", nil); err != nil || len(transactions) != 4 {
+			t.Errorf("unexpected result: %v, %v", transactions, err)
+		}
+	})
+
+	t.Run("should list new efakturas", func(t *testing.T) {
+		if efakturas, err := c.ListNewEfakturas(ctx, nil); err != nil || len(efakturas) != 2 {
+			t.Errorf("unexpected result: %v, %v", efakturas, err)
+		}
+	})
+
+	t.Run("should read payment", func(t *testing.T) {
+		if p, err := c.ReadPayment(ctx, "checking", "payment"); err != nil || p.ID != "payment" {
+			t.Errorf("unexpected result: %v, %v", p, err)
+		}
+	})
+
+	t.Run("should list standing orders", func(t *testing.T) {
+		if orders, err := c.ListStandingOrders(ctx, "checking"); err != nil || len(orders) != 1 {
+			t.Errorf("unexpected result: %v, %v", orders, err)
+		}
+	})
+
+	t.Run("should list cards", func(t *testing.T) {
+		if cards, err := c.ListCards(ctx); err != nil || len(cards) != 1 {
+			t.Errorf("unexpected result: %v, %v", cards, err)
+		}
+	})
+
+	t.Run("should get customer", func(t *testing.T) {
+		if customer, err := c.GetCustomer(ctx); err != nil || customer.CustomerID != "customer" {
+			t.Errorf("unexpected result: %v, %v", customer, err)
+		}
+	})
+
+	t.Run("should return account not found", func(t *testing.T) {
+		if _, err := c.ListPayments(ctx, "unknown", nil); !errors.Is(err, sbanken.ErrAccountNotFound) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestAuthorization(t *testing.T) {
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	cfg := srv.Config()
+	cfg.ClientSecret = "wrong-secret"
+
+	_, err := sbanken.NewClient(context.Background(), cfg, srv.Client())
+
+	var authErr *sbanken.AuthError
+	if !errors.As(err, &authErr) {
+		t.Errorf("expected AuthError: got %v", err)
+	}
+}
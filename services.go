@@ -0,0 +1,84 @@
+package sbanken
+
+import "context"
+
+// The service interfaces group the methods of Client per resource, so that code depending on
+// the client can be tested with a mock, such as those in the sbankenmock package.
+// The Iterate methods are left out, as the iterators can not be created without a Client.
+// Use the ListAll methods instead when depending on the interfaces.
+
+// AccountsService is the interface of the accounts methods of Client.
+type AccountsService interface {
+	ListAccounts(ctx context.Context) ([]Account, error)
+	ListAccountsPage(ctx context.Context) (*AccountPage, error)
+	ReadAccount(ctx context.Context, accountID string) (Account, error)
+}
+
+// CardsService is the interface of the cards methods of Client.
+type CardsService interface {
+	ListCards(ctx context.Context) ([]Card, error)
+	ListCardsPage(ctx context.Context) (*CardPage, error)
+}
+
+// CustomersService is the interface of the customers methods of Client.
+type CustomersService interface {
+	GetCustomer(ctx context.Context) (Customer, error)
+}
+
+// EfakturasService is the interface of the efakturas methods of Client.
+type EfakturasService interface {
+	ListEfakturas(ctx context.Context, q *EfakturaListQuery) ([]Efaktura, error)
+	ListEfakturasPage(ctx context.Context, q *EfakturaListQuery) (*EfakturaPage, error)
+	ListAllEfakturas(ctx context.Context, q *EfakturaListQuery, opts *PageOptions) ([]Efaktura, error)
+	ListNewEfakturas(ctx context.Context, q *EfakturaListQuery) ([]Efaktura, error)
+	ListNewEfakturasPage(ctx context.Context, q *EfakturaListQuery) (*EfakturaPage, error)
+	ListAllNewEfakturas(ctx context.Context, q *EfakturaListQuery, opts *PageOptions) ([]Efaktura, error)
+	ReadEfaktura(ctx context.Context, efakturaID string) (Efaktura, error)
+	PayEfaktura(ctx context.Context, q *EfakturaPayQuery) error
+}
+
+// PaymentsService is the interface of the payments methods of Client.
+type PaymentsService interface {
+	ListPayments(ctx context.Context, accountID string, q *PaymentListQuery) ([]Payment, error)
+	ListPaymentsPage(ctx context.Context, accountID string, q *PaymentListQuery) (*PaymentPage, error)
+	ListAllPayments(ctx context.Context, accountID string, q *PaymentListQuery, opts *PageOptions) ([]Payment, error)
+	ReadPayment(ctx context.Context, accountID string, paymentID string) (Payment, error)
+}
+
+// StandingOrdersService is the interface of the standing orders methods of Client.
+type StandingOrdersService interface {
+	ListStandingOrders(ctx context.Context, accountID string) ([]StandingOrder, error)
+	ListStandingOrdersPage(ctx context.Context, accountID string) (*StandingOrderPage, error)
+}
+
+// TransactionsService is the interface of the transactions methods of Client.
+type TransactionsService interface {
+	ListTransactions(ctx context.Context, accountID string, q *TransactionListQuery) ([]Transaction, error)
+	ListTransactionsPage(ctx context.Context, accountID string, q *TransactionListQuery) (*TransactionPage, error)
+	ListAllTransactions(ctx context.Context, accountID string, q *TransactionListQuery, opts *PageOptions) ([]Transaction, error)
+	StreamTransactions(ctx context.Context, accountID string, q *TransactionListQuery, fn func(Transaction) error) (*TransactionPage, error)
+	ListArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery) ([]Transaction, error)
+	ListArchivedTransactionsPage(ctx context.Context, accountID string, q *TransactionListQuery) (*TransactionPage, error)
+	ListAllArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery, opts *PageOptions) ([]Transaction, error)
+	StreamArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery, fn func(Transaction) error) (*TransactionPage, error)
+}
+
+// TransfersService is the interface of the transfers methods of Client.
+type TransfersService interface {
+	Transfer(ctx context.Context, q *TransferQuery) error
+}
+
+// API is the interface of all the service methods of Client, together with Ping.
+type API interface {
+	AccountsService
+	CardsService
+	CustomersService
+	EfakturasService
+	PaymentsService
+	StandingOrdersService
+	TransactionsService
+	TransfersService
+	Ping(ctx context.Context) error
+}
+
+var _ API = (*Client)(nil)
@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -12,55 +11,57 @@ import (
 // StandingOrder represents a standing order (repeated transfers and payments).
 // Sbanken API documentation: https://publicapi.sbanken.no/openapi/apibeta/index.html#/StandingOrders
 type StandingOrder struct {
-	FreeTerms              []string `json:"freeTerms"`
-	BeneficiaryName        string   `json:"beneficiaryName"`
-	CID                    string   `json:"cId"`
-	CreditAccountNumber    string   `json:"creditAccountNumber"`
-	DebitAccountNumber     string   `json:"debitAccountNumber"`
-	Frequency              string   `json:"frequency"`
-	LastPaymentDate        string   `json:"lastPaymentDate"`
-	NextDueDate            string   `json:"nextDueDate"`
-	StandingOrderEndDate   string   `json:"standingOrderEndDate"`
-	StandingOrderStartDate string   `json:"standingOrderStartDate"`
-	StandingOrderType      string   `json:"standingOrderType"`
-	Amount                 float32  `json:"amount"`
-	StandingOrderID        int      `json:"standingOrderId"`
+	FreeTerms              []string      `json:"freeTerms"`
+	BeneficiaryName        string        `json:"beneficiaryName"`
+	CID                    KID           `json:"cId"`
+	CreditAccountNumber    AccountNumber `json:"creditAccountNumber"`
+	DebitAccountNumber     AccountNumber `json:"debitAccountNumber"`
+	Frequency              string        `json:"frequency"`
+	LastPaymentDate        Date          `json:"lastPaymentDate"`
+	NextDueDate            Date          `json:"nextDueDate"`
+	StandingOrderEndDate   Date          `json:"standingOrderEndDate"`
+	StandingOrderStartDate Date          `json:"standingOrderStartDate"`
+	StandingOrderType      string        `json:"standingOrderType"`
+	Amount                 Money         `json:"amount"`
+	StandingOrderID        int           `json:"standingOrderId"`
+}
+
+// StandingOrderPage represents a list of standing orders, together with the total number of available items and the trace ID of the response.
+type StandingOrderPage struct {
+	TraceID        string
+	Items          []StandingOrder
+	AvailableItems int
 }
 
 // ListStandingOrders lists the standing orders for repeated transfers and payments. The accoundID are required.
 func (c *Client) ListStandingOrders(ctx context.Context, accountID string) ([]StandingOrder, error) {
+	page, err := c.ListStandingOrdersPage(ctx, accountID)
+	if page == nil {
+		return nil, err
+	}
+
+	return page.Items, err
+}
+
+// ListStandingOrdersPage is like ListStandingOrders, but also returns the number of available items and the trace ID.
+func (c *Client) ListStandingOrdersPage(ctx context.Context, accountID string) (*StandingOrderPage, error) {
 	if accountID == "" {
 		return nil, ErrMissingAccountID
 	}
 
-	url := fmt.Sprintf("%s/v1/StandingOrders/%s", c.bankBaseURL, accountID)
+	url := fmt.Sprintf("%s/%s/StandingOrders/%s", c.bankBaseURL, c.apiVersion, accountID)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[StandingOrder](ctx, c, "ListStandingOrders", &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
-	}
-
-	data := struct {
-		StandingOrders []StandingOrder `json:"items"`
-		transport.HTTPResponse
-	}{}
-
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.StandingOrders, fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return data.StandingOrders, &Error{
-			"ListStandingOrders",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	if data == nil {
+		return nil, err
 	}
 
-	return data.StandingOrders, nil
+	return &StandingOrderPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
-	"time"
 
 	"github.com/engvik/sbanken-go/internal/transport"
 )
@@ -17,12 +16,12 @@ var testStandingOrder = StandingOrder{
 	CreditAccountNumber:    "11111111111",
 	DebitAccountNumber:     "2222222222",
 	Frequency:              "monthly",
-	LastPaymentDate:        time.Now().String(),
-	NextDueDate:            time.Now().String(),
-	StandingOrderEndDate:   time.Now().String(),
-	StandingOrderStartDate: time.Now().String(),
+	LastPaymentDate:        mustParseDate("2021-01-20T00:00:00"),
+	NextDueDate:            mustParseDate("2021-02-20T00:00:00"),
+	StandingOrderEndDate:   mustParseDate("2022-12-20T00:00:00"),
+	StandingOrderStartDate: mustParseDate("2020-01-20T00:00:00"),
 	StandingOrderType:      "type",
-	Amount:                 1337.00,
+	Amount:                 133700,
 	StandingOrderID:        19,
 }
 
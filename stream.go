@@ -0,0 +1,203 @@
+package sbanken
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+// stream performs the request and calls fn for every element of the items array of the response
+// as it is decoded, without buffering the response body. Errors returned by fn stop the decoding
+// and are returned unchanged. Other errors, including decoding errors, are returned as *Error.
+//
+// Items are not passed to fn when the response is not successful, or when isError is set before
+// the items in the body. As the body is not buffered, an isError set after the items is only seen
+// once the items have been passed to fn, and the error is then returned after them.
+func stream[T any](ctx context.Context, c *Client, caller string, r *transport.HTTPRequest, fn func(T) error) (*transport.HTTPResponse, error) {
+	if r.Operation == "" {
+		r.Operation = caller
+	}
+
+	var (
+		envelope transport.HTTPResponse
+		decoded  bool
+		fnErr    error
+	)
+
+	call := func(item T) error {
+		fnErr = fn(item)
+		return fnErr
+	}
+
+	r.Stream = func(body io.Reader) error {
+		var err error
+		envelope, err = decodeStream(body, call)
+		decoded = true
+
+		return err
+	}
+
+	res, sc, err := c.transport.Request(ctx, r)
+	if err != nil {
+		if fnErr != nil {
+			return &envelope, fnErr
+		}
+
+		if decoded {
+			return &envelope, streamError(caller, err, sc)
+		}
+
+		return nil, newRequestError(caller, err)
+	}
+
+	// The body is buffered when the response was not streamed, e.g. for error responses,
+	// whose items are not passed to fn.
+	if !decoded && len(res) > 0 {
+		if sc != http.StatusOK {
+			call = func(T) error { return nil }
+		}
+
+		envelope, err = decodeStream(bytes.NewReader(res), call)
+		if fnErr != nil {
+			return &envelope, fnErr
+		}
+
+		if err != nil {
+			return &envelope, newDecodeError(caller, err, sc)
+		}
+	}
+
+	if envelope.IsError || sc != http.StatusOK {
+		return &envelope, newResponseError(caller, envelope, sc)
+	}
+
+	return &envelope, nil
+}
+
+// streamError returns the error of a streamed response body. Failures to read the body are
+// request errors, while other failures are decoding errors.
+func streamError(caller string, err error, statusCode int) *Error {
+	var (
+		netErr        *NetworkError
+		unexpectedErr *UnexpectedResponseError
+	)
+
+	if errors.As(err, &netErr) || errors.As(err, &unexpectedErr) {
+		return newRequestError(caller, err)
+	}
+
+	return newDecodeError(caller, err, statusCode)
+}
+
+// decodeStream decodes a response envelope from r, calling fn for every element of the items array.
+// The items are skipped if isError is set before them.
+func decodeStream[T any](r io.Reader, fn func(T) error) (transport.HTTPResponse, error) {
+	var res transport.HTTPResponse
+
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return res, err
+	}
+
+	// The envelope fields are collected and decoded at the end, as they may come before or after the items.
+	fields := map[string]json.RawMessage{}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return res, fmt.Errorf("Decode: %w", err)
+		}
+
+		key, _ := tok.(string)
+		if key != "items" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return res, fmt.Errorf("Decode: %w", err)
+			}
+
+			fields[key] = raw
+
+			continue
+		}
+
+		if isError(fields) {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return res, fmt.Errorf("Decode: %w", err)
+			}
+
+			continue
+		}
+
+		tok, err = dec.Token()
+		if err != nil {
+			return res, fmt.Errorf("Decode: %w", err)
+		}
+
+		if tok == nil {
+			continue
+		}
+
+		if d, ok := tok.(json.Delim); !ok || d != '[' {
+			return res, fmt.Errorf("Decode: unexpected items %v", tok)
+		}
+
+		for dec.More() {
+			var item T
+			if err := dec.Decode(&item); err != nil {
+				return res, fmt.Errorf("Decode: %w", err)
+			}
+
+			if err := fn(item); err != nil {
+				return res, err
+			}
+		}
+
+		if err := expectDelim(dec, ']'); err != nil {
+			return res, err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return res, err
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return res, fmt.Errorf("Decode: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return res, fmt.Errorf("Decode: %w", err)
+	}
+
+	return res, nil
+}
+
+// isError reports whether the envelope fields decoded so far set isError.
+func isError(fields map[string]json.RawMessage) bool {
+	var v bool
+	_ = json.Unmarshal(fields["isError"], &v)
+
+	return v
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("Decode: %w", err)
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("Decode: expected %v, got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package sbanken
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func testTransactionsBody(tb testing.TB, n int) []byte {
+	tb.Helper()
+
+	transactions := make([]Transaction, n)
+	for i := range transactions {
+		transactions[i] = testTransaction
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"availableItems": n,
+		"traceId":        "trace",
+		"items":          transactions,
+	})
+	if err != nil {
+		tb.Fatalf("error setting up test: %v", err)
+	}
+
+	return b
+}
+
+func TestStreamTransactions(t *testing.T) {
+	ctx := context.Background()
+	c, err := newTestClient(ctx, t)
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name      string
+		accountID string
+		behavior  string
+		fnErr     error
+		exp       []Transaction
+		expErr    error
+	}{
+		{
+			name:   "should fail when no accountID is passed",
+			expErr: ErrMissingAccountID,
+		},
+		{
+			name:      "should return error when error occurs",
+			accountID: "test-account",
+			behavior:  "fail",
+			expErr:    getTestError("ListTransactions"),
+		},
+		{
+			name:      "should stream transactions",
+			accountID: "test-account",
+			exp:       []Transaction{testTransaction},
+		},
+		{
+			name:      "should return callback error",
+			accountID: "test-account",
+			fnErr:     errStop,
+			exp:       []Transaction{testTransaction},
+			expErr:    errStop,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(ctx, testBehavior("test-behavior"), tc.behavior)
+
+			var transactions []Transaction
+
+			_, err := c.StreamTransactions(ctx, tc.accountID, nil, func(t Transaction) error {
+				transactions = append(transactions, t)
+				return tc.fnErr
+			})
+
+			if tc.expErr != nil {
+				if err == nil || err.Error() != tc.expErr.Error() {
+					t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(transactions, tc.exp) {
+				t.Errorf("unexpected transactions: got %v, exp %v", transactions, tc.exp)
+			}
+		})
+	}
+}
+
+func TestStreamTransactionsFromServer(t *testing.T) {
+	ctx := context.Background()
+	body := testTransactionsBody(t, 1000)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/connect/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api/v1/Transactions/archive/test-account", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(ctx, &Config{
+		ClientID:     "some-client-id",
+		ClientSecret: "some-client-secret",
+		BaseURL:      srv.URL + "/api",
+		AuthURL:      srv.URL + "/connect/token",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("error setting up test: %v", err)
+	}
+
+	n := 0
+
+	page, err := c.StreamArchivedTransactions(ctx, "test-account", nil, func(tr Transaction) error {
+		if !reflect.DeepEqual(tr, testTransaction) {
+			t.Fatalf("unexpected transaction: got %v", tr)
+		}
+
+		n++
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 1000 || page.AvailableItems != 1000 || page.TraceID != "trace" {
+		t.Errorf("unexpected result: got %d transactions, page %+v", n, page)
+	}
+}
+
+func TestStreamTransactionsErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		expItems   int
+		expErrType bool
+		expErr     error
+	}{
+		{name: "should wrap truncated body in Error", status: http.StatusOK, body: `{"items":[{}`, expItems: 1, expErrType: true},
+		{name: "should wrap malformed body in Error", status: http.StatusOK, body: `{"items":[}`, expErrType: true},
+		{name: "should not pass items of error envelope", status: http.StatusOK, body: `{"isError":true,"errorType":"System","items":[{}]}`, expErrType: true},
+		{name: "should not pass items of error response", status: http.StatusInternalServerError, body: `{"items":[{}],"isError":true}`, expErrType: true},
+		{name: "should keep status code of malformed error response", status: http.StatusServiceUnavailable, body: `{"items":[`, expErrType: true, expErr: ErrServiceUnavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/connect/token", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+			})
+			mux.HandleFunc("/api/v1/Transactions/test-account", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			})
+
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			c, err := NewClient(ctx, &Config{
+				ClientID:     "some-client-id",
+				ClientSecret: "some-client-secret",
+				BaseURL:      srv.URL + "/api",
+				AuthURL:      srv.URL + "/connect/token",
+			}, srv.Client())
+			if err != nil {
+				t.Fatalf("error setting up test: %v", err)
+			}
+
+			n := 0
+
+			_, err = c.StreamTransactions(ctx, "test-account", nil, func(Transaction) error {
+				n++
+				return nil
+			})
+
+			var sErr *Error
+			if errors.As(err, &sErr) != tc.expErrType {
+				t.Errorf("unexpected error: got %T %v", err, err)
+			}
+
+			if tc.expErr != nil && !errors.Is(err, tc.expErr) {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.expErr)
+			}
+
+			if n != tc.expItems {
+				t.Errorf("unexpected number of transactions: got %d, exp %d", n, tc.expItems)
+			}
+		})
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		exp        []int
+		expTraceID string
+		expErr     bool
+	}{
+		{name: "should decode items", body: `{"items":[1,2,3],"traceId":"trace"}`, exp: []int{1, 2, 3}, expTraceID: "trace"},
+		{name: "should decode envelope before items", body: `{"traceId":"trace","availableItems":3,"items":[1]}`, exp: []int{1}, expTraceID: "trace"},
+		{name: "should handle null items", body: `{"items":null}`},
+		{name: "should handle missing items", body: `{"isError":true}`},
+		{name: "should skip items after isError", body: `{"isError":true,"items":[1,2],"traceId":"trace"}`, expTraceID: "trace"},
+		{name: "should decode items before isError", body: `{"items":[1,2],"isError":true}`, exp: []int{1, 2}},
+		{name: "should return error on invalid item", body: `{"items":[1,"a"]}`, exp: []int{1}, expErr: true},
+		{name: "should return error on truncated body", body: `{"items":[1,2`, exp: []int{1, 2}, expErr: true},
+		{name: "should return error on non object body", body: `[1,2]`, expErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var items []int
+
+			res, err := decodeStream(strings.NewReader(tc.body), func(i int) error {
+				items = append(items, i)
+				return nil
+			})
+
+			if (err != nil) != tc.expErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(items, tc.exp) {
+				t.Errorf("unexpected items: got %v, exp %v", items, tc.exp)
+			}
+
+			if res.TraceID != tc.expTraceID {
+				t.Errorf("unexpected trace ID: got %q, exp %q", res.TraceID, tc.expTraceID)
+			}
+		})
+	}
+}
+
+const benchmarkTransactions = 1000
+
+// reportAllocsPerTransaction reports the number of allocations per decoded transaction.
+func reportAllocsPerTransaction(b *testing.B, run func()) {
+	b.Helper()
+	b.ReportAllocs()
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		run()
+	}
+
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+
+	b.ReportMetric(float64(after.Mallocs-before.Mallocs)/float64(b.N*benchmarkTransactions), "allocs/transaction")
+}
+
+func BenchmarkDecodeTransactionsBuffered(b *testing.B) {
+	body := testTransactionsBody(b, benchmarkTransactions)
+
+	reportAllocsPerTransaction(b, func() {
+		data, err := ioutil.ReadAll(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var res apiResponse[Transaction]
+		if err := json.Unmarshal(data, &res); err != nil {
+			b.Fatal(err)
+		}
+
+		for _, t := range res.Items {
+			_ = t
+		}
+	})
+}
+
+func BenchmarkDecodeTransactionsStream(b *testing.B) {
+	body := testTransactionsBody(b, benchmarkTransactions)
+
+	reportAllocsPerTransaction(b, func() {
+		_, err := decodeStream(bytes.NewReader(body), func(t Transaction) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	})
+}
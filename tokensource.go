@@ -0,0 +1,59 @@
+package sbanken
+
+import (
+	"net/http"
+
+	"github.com/engvik/sbanken-go/internal/transport"
+)
+
+// Token is an access token for the API.
+type Token = transport.Token
+
+// TokenSource supplies access tokens to the client. Set it on Config to use another
+// source than the client credentials grant, such as a token fetched by another program:
+//
+//	src := sbanken.CachingTokenSource(
+//		sbanken.NewClientCredentialsTokenSource(clientID, clientSecret, "", nil),
+//		filepath.Join(os.TempDir(), "sbanken-token.json"),
+//	)
+//
+//	c, err := sbanken.NewClient(ctx, &sbanken.Config{TokenSource: src}, nil)
+//
+// The client keeps the token in memory and only asks the source for a new one when
+// the current token is about to expire.
+type TokenSource = transport.TokenSource
+
+// SkewTokenSource is a TokenSource that is told the TokenRefreshSkew of the client, so that it does
+// not return a token the client considers about to expire. CachingTokenSource implements it, and
+// sources wrapping another source should implement it and pass the skew on.
+type SkewTokenSource = transport.SkewTokenSource
+
+// NewClientCredentialsTokenSource returns a TokenSource fetching tokens from the identity server
+// with the client credentials grant. If authURL is empty, DefaultAuthURL will be used.
+// If httpClient is nil, http.DefaultClient will be used.
+func NewClientCredentialsTokenSource(clientID, clientSecret, authURL string, httpClient *http.Client) TokenSource {
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+
+	return transport.NewClientCredentialsTokenSource(clientID, clientSecret, authURL, httpClient)
+}
+
+// StaticTokenSource returns a TokenSource that always returns the given access token.
+// The token is never refreshed, so requests fail once it expires.
+func StaticTokenSource(accessToken string) TokenSource {
+	return transport.StaticTokenSource(accessToken)
+}
+
+// FileTokenSource returns a TokenSource reading the token from a file. The file holds either
+// the raw access token, or a JSON encoded Token as written by CachingTokenSource.
+func FileTokenSource(path string) TokenSource {
+	return transport.FileTokenSource(path)
+}
+
+// CachingTokenSource returns a TokenSource persisting the tokens of src to a file, so that
+// short-lived programs can reuse the token between runs instead of authorizing every time.
+// The file is created with permissions 0600, and should be kept private as it grants access to the API.
+func CachingTokenSource(src TokenSource, path string) TokenSource {
+	return transport.CachingTokenSource(src, path)
+}
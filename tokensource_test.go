@@ -0,0 +1,82 @@
+package sbanken
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokenSource(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "some-client-id", "some-client-secret")
+
+	newClient := func(src TokenSource) (*Client, error) {
+		return NewClient(ctx, &Config{
+			BaseURL:     srv.URL + "/api",
+			APIVersion:  "v2",
+			TokenSource: src,
+		}, srv.Client())
+	}
+
+	t.Run("should use static token", func(t *testing.T) {
+		c, err := newClient(StaticTokenSource("test-token"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should reuse cached token between clients", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+
+		c, err := newClient(CachingTokenSource(
+			NewClientCredentialsTokenSource("some-client-id", "some-client-secret", srv.URL+"/connect/token", srv.Client()),
+			path,
+		))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected token file: %v", err)
+		}
+
+		// The wrapped source would fail, so the second client must use the cached token.
+		c, err = newClient(CachingTokenSource(
+			NewClientCredentialsTokenSource("some-client-id", "wrong-secret", srv.URL+"/connect/token", srv.Client()),
+			path,
+		))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should read token from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		if err := ioutil.WriteFile(path, []byte("test-token\n"), 0600); err != nil {
+			t.Fatalf("error setting up test: %v", err)
+		}
+
+		c, err := newClient(FileTokenSource(path))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.ListAccounts(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
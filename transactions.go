@@ -2,7 +2,6 @@ package sbanken
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,16 +15,16 @@ import (
 type Transaction struct {
 	CardDetails                 CardDetails        `json:"cardDetails"`
 	TransactionDetails          TransactionDetails `json:"transactionDetails"`
-	AccountingDate              string             `json:"accountingDate"`
-	InterestDate                string             `json:"interestDate"`
-	OtherAccountNumber          string             `json:"otherAccountNumber"`
+	AccountingDate              Date               `json:"accountingDate"`
+	InterestDate                Date               `json:"interestDate"`
+	OtherAccountNumber          AccountNumber      `json:"otherAccountNumber"`
 	Text                        string             `json:"text"`
 	TransactionType             string             `json:"transactionType"`
 	TransactionTypeText         string             `json:"transactionTypeText"`
 	ReservationType             string             `json:"reservationType"`
 	TransactionID               string             `json:"transactionId"`
 	Source                      string             `json:"source"`
-	Amount                      float32            `json:"amount"`
+	Amount                      Money              `json:"amount"`
 	TransactionTypeCode         int                `json:"transactionTypeCode"`
 	IsReservation               bool               `json:"isReservation"`
 	CardDetailsSpecified        bool               `json:"cardDetailsSpecified"`
@@ -41,9 +40,9 @@ type CardDetails struct {
 	MerchantCity                string  `json:"merchantCity"`
 	MerchantName                string  `json:"merchantName"`
 	OriginalCurrencyCode        string  `json:"originalCurrencyCode"`
-	PurchaseDate                string  `json:"purchaseDate"`
+	PurchaseDate                Date    `json:"purchaseDate"`
 	TransactionID               string  `json:"transactionId"`
-	CurrencyAmount              float32 `json:"currencyAmount"`
+	CurrencyAmount              Money   `json:"currencyAmount"`
 	CurrencyRate                float32 `json:"currencyRate"`
 }
 
@@ -55,7 +54,7 @@ type TransactionDetails struct {
 	AmountDescription      string `json:"amountDescription"`
 	ReceiverName           string `json:"receiverName"`
 	PayerName              string `json:"payerName"`
-	RegistrationDate       string `json:"registrationDate"`
+	RegistrationDate       Date   `json:"registrationDate"`
 	NumericReference       int    `json:"numericReference"`
 }
 
@@ -95,61 +94,153 @@ func (q *TransactionListQuery) QueryString(u string) (string, error) {
 	return query.Encode(), nil
 }
 
+// TransactionPage represents a page of transactions, together with the total number of available items and the trace ID of the response.
+type TransactionPage struct {
+	TraceID        string
+	Items          []Transaction
+	AvailableItems int
+}
+
 // ListTransactions returns the latest transactions of the given account.
 func (c *Client) ListTransactions(ctx context.Context, accountID string, q *TransactionListQuery) ([]Transaction, error) {
+	page, err := c.ListTransactionsPage(ctx, accountID, q)
+	if page == nil {
+		return nil, err
+	}
+
+	return page.Items, err
+}
+
+// ListTransactionsPage is like ListTransactions, but also returns the number of available items and the trace ID.
+func (c *Client) ListTransactionsPage(ctx context.Context, accountID string, q *TransactionListQuery) (*TransactionPage, error) {
 	if accountID == "" {
 		return nil, ErrMissingAccountID
 	}
 
-	url := fmt.Sprintf("%s/v1/Transactions/%s", c.bankBaseURL, accountID)
+	url := fmt.Sprintf("%s/%s/Transactions/%s", c.bankBaseURL, c.apiVersion, accountID)
 
-	if q != nil {
-		qs, err := q.QueryString(url)
-		if err != nil {
-			return nil, fmt.Errorf("QueryString: %w", err)
-		}
+	return c.listTransactions(ctx, url, q, "ListTransactions")
+}
 
-		url = fmt.Sprintf("%s?%s", url, qs)
+// ListArchivedTransactions returns archived transactions.
+func (c *Client) ListArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery) ([]Transaction, error) {
+	page, err := c.ListArchivedTransactionsPage(ctx, accountID, q)
+	if page == nil {
+		return nil, err
 	}
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
-		Method: http.MethodGet,
-		URL:    url,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
+	return page.Items, err
+}
+
+// ListArchivedTransactionsPage is like ListArchivedTransactions, but also returns the number of available items and the trace ID.
+func (c *Client) ListArchivedTransactionsPage(ctx context.Context, accountID string, q *TransactionListQuery) (*TransactionPage, error) {
+	if accountID == "" {
+		return nil, ErrMissingAccountID
 	}
 
-	data := struct {
-		Transactions []Transaction `json:"items"`
-		transport.HTTPResponse
-	}{}
+	url := fmt.Sprintf("%s/%s/Transactions/archive/%s", c.bankBaseURL, c.apiVersion, accountID)
 
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Transactions, fmt.Errorf("Unmarshal: %w", err)
-	}
+	return c.listTransactions(ctx, url, q, "ListArchivedTransactions")
+}
 
-	if data.IsError || sc != http.StatusOK {
-		return data.Transactions, &Error{
-			"ListTransactions",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+// IterateTransactions returns an iterator over all transactions of the given account matching the query.
+// Index and Length of the query are managed by the iterator.
+func (c *Client) IterateTransactions(accountID string, q *TransactionListQuery, opts *PageOptions) *TransactionIterator {
+	url := fmt.Sprintf("%s/%s/Transactions/%s", c.bankBaseURL, c.apiVersion, accountID)
+
+	return c.newTransactionIterator(accountID, url, q, opts, "ListTransactions")
+}
+
+// IterateArchivedTransactions returns an iterator over all archived transactions of the given account matching the query.
+// Index and Length of the query are managed by the iterator.
+func (c *Client) IterateArchivedTransactions(accountID string, q *TransactionListQuery, opts *PageOptions) *TransactionIterator {
+	url := fmt.Sprintf("%s/%s/Transactions/archive/%s", c.bankBaseURL, c.apiVersion, accountID)
+
+	return c.newTransactionIterator(accountID, url, q, opts, "ListArchivedTransactions")
+}
+
+// ListAllTransactions fetches all transactions of the given account matching the query, one page at a time.
+func (c *Client) ListAllTransactions(ctx context.Context, accountID string, q *TransactionListQuery, opts *PageOptions) ([]Transaction, error) {
+	return c.IterateTransactions(accountID, q, opts).all(ctx)
+}
+
+// ListAllArchivedTransactions fetches all archived transactions of the given account matching the query, one page at a time.
+func (c *Client) ListAllArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery, opts *PageOptions) ([]Transaction, error) {
+	return c.IterateArchivedTransactions(accountID, q, opts).all(ctx)
+}
+
+// StreamTransactions calls fn for each of the latest transactions of the given account as it is decoded
+// from the response, so that large pages are never held in memory at once. Streaming stops at the first
+// error returned by fn, which is returned unchanged, and other errors are returned as *Error. The returned
+// page has the number of available items and the trace ID, but no items.
+//
+// Items of failed responses are not passed to fn, except when the API reports the error in the envelope
+// after the items. The error is then only known, and returned, once all the items have been passed to fn.
+func (c *Client) StreamTransactions(ctx context.Context, accountID string, q *TransactionListQuery, fn func(Transaction) error) (*TransactionPage, error) {
+	if accountID == "" {
+		return nil, ErrMissingAccountID
 	}
 
-	return data.Transactions, nil
+	url := fmt.Sprintf("%s/%s/Transactions/%s", c.bankBaseURL, c.apiVersion, accountID)
+
+	return c.streamTransactions(ctx, url, q, "ListTransactions", fn)
 }
 
-// ListArchivedTransactions returns archived transactions.
-func (c *Client) ListArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery) ([]Transaction, error) {
+// StreamArchivedTransactions is like StreamTransactions, but for archived transactions.
+func (c *Client) StreamArchivedTransactions(ctx context.Context, accountID string, q *TransactionListQuery, fn func(Transaction) error) (*TransactionPage, error) {
 	if accountID == "" {
 		return nil, ErrMissingAccountID
 	}
 
-	url := fmt.Sprintf("%s/v1/Transactions/archive/%s", c.bankBaseURL, accountID)
+	url := fmt.Sprintf("%s/%s/Transactions/archive/%s", c.bankBaseURL, c.apiVersion, accountID)
+
+	return c.streamTransactions(ctx, url, q, "ListArchivedTransactions", fn)
+}
+
+// TransactionIterator iterates over transactions across pages.
+//
+//	it := c.IterateTransactions(accountID, nil, nil)
+//	for it.Next(ctx) {
+//		t := it.Transaction()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TransactionIterator struct {
+	*Iterator[Transaction]
+}
+
+func (c *Client) newTransactionIterator(accountID string, url string, q *TransactionListQuery, opts *PageOptions, caller string) *TransactionIterator {
+	var query TransactionListQuery
+	if q != nil {
+		query = *q
+	}
+
+	it := newIterator(query.Index, query.Length, opts, func(ctx context.Context, index, length string) ([]Transaction, int, error) {
+		query.Index, query.Length = index, length
+
+		page, err := c.listTransactions(ctx, url, &query, caller)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return page.Items, page.AvailableItems, nil
+	})
+
+	if accountID == "" {
+		it.pager.err = ErrMissingAccountID
+	}
+
+	return &TransactionIterator{it}
+}
 
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.Item()
+}
+
+func (c *Client) listTransactions(ctx context.Context, url string, q *TransactionListQuery, caller string) (*TransactionPage, error) {
 	if q != nil {
 		qs, err := q.QueryString(url)
 		if err != nil {
@@ -159,32 +250,41 @@ func (c *Client) ListArchivedTransactions(ctx context.Context, accountID string,
 		url = fmt.Sprintf("%s?%s", url, qs)
 	}
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	data, err := do[Transaction](ctx, c, caller, &transport.HTTPRequest{
 		Method: http.MethodGet,
 		URL:    url,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("request: %w", err)
+	if data == nil {
+		return nil, err
 	}
 
-	data := struct {
-		Transactions []Transaction `json:"items"`
-		transport.HTTPResponse
-	}{}
+	return &TransactionPage{
+		Items:          data.Items,
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
+}
+
+func (c *Client) streamTransactions(ctx context.Context, url string, q *TransactionListQuery, caller string, fn func(Transaction) error) (*TransactionPage, error) {
+	if q != nil {
+		qs, err := q.QueryString(url)
+		if err != nil {
+			return nil, fmt.Errorf("QueryString: %w", err)
+		}
 
-	if err := json.Unmarshal(res, &data); err != nil {
-		return data.Transactions, fmt.Errorf("Unmarshal: %w", err)
+		url = fmt.Sprintf("%s?%s", url, qs)
 	}
 
-	if data.IsError || sc != http.StatusOK {
-		return data.Transactions, &Error{
-			"ListTransactions",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
+	data, err := stream(ctx, c, caller, &transport.HTTPRequest{
+		Method: http.MethodGet,
+		URL:    url,
+	}, fn)
+	if data == nil {
+		return nil, err
 	}
 
-	return data.Transactions, nil
+	return &TransactionPage{
+		AvailableItems: data.AvailableItems,
+		TraceID:        data.TraceID,
+	}, err
 }
@@ -3,6 +3,7 @@ package sbanken
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -21,11 +22,11 @@ var testTransaction = Transaction{
 		AmountDescription:      "amount-desc",
 		ReceiverName:           "name nameson",
 		PayerName:              "pay payson",
-		RegistrationDate:       time.Now().String(),
+		RegistrationDate:       mustParseDate("2021-01-29T13:37:00"),
 		NumericReference:       15,
 	},
-	AccountingDate:              time.Now().String(),
-	InterestDate:                time.Now().String(),
+	AccountingDate:              mustParseDate("2021-01-30T00:00:00"),
+	InterestDate:                mustParseDate("2021-01-30T00:00:00"),
 	OtherAccountNumber:          "123141423",
 	Text:                        "transaction",
 	TransactionType:             "transaction",
@@ -33,7 +34,7 @@ var testTransaction = Transaction{
 	TransactionID:               "1337",
 	ReservationType:             "reservation",
 	Source:                      "source",
-	Amount:                      999.99,
+	Amount:                      99999,
 	IsReservation:               true,
 	OtherAccountNumberSpecified: true,
 	TransactionDetailSpecified:  true,
@@ -206,7 +207,7 @@ func TestListArchivedTransactions(t *testing.T) {
 			q:         nil,
 			behavior:  "fail",
 			exp:       nil,
-			expErr:    getTestError("ListTransactions"),
+			expErr:    getTestError("ListArchivedTransactions"),
 		},
 		{
 			name:      "should list transactions without query",
@@ -245,3 +246,35 @@ func TestListArchivedTransactions(t *testing.T) {
 		})
 	}
 }
+
+func TestListTransactionsPage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return available items and trace ID", func(t *testing.T) {
+		c, _ := newTestPagingClient(ctx, t, 812, 0)
+
+		page, err := c.ListTransactionsPage(ctx, "test-account", &TransactionListQuery{Length: "50"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(page.Items) != 50 || page.AvailableItems != 812 || page.TraceID != testTraceID {
+			t.Errorf("unexpected page: got %d of %d (%s)", len(page.Items), page.AvailableItems, page.TraceID)
+		}
+	})
+
+	t.Run("should return trace ID in error", func(t *testing.T) {
+		c, _ := newTestPagingClient(ctx, t, 812, 1)
+
+		_, err := c.ListArchivedTransactionsPage(ctx, "test-account", &TransactionListQuery{Index: "100"})
+
+		var sErr *Error
+		if !errors.As(err, &sErr) {
+			t.Fatalf("unexpected error: got %v", err)
+		}
+
+		if sErr.TraceID != testTraceID {
+			t.Errorf("unexpected trace ID: got %s, exp %s", sErr.TraceID, testTraceID)
+		}
+	})
+}
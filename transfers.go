@@ -11,10 +11,10 @@ import (
 
 // TransferQuery represents the query for transferring between accounts.
 type TransferQuery struct {
-	FromAccountID string  `json:"fromAccountId"`
-	ToAccountID   string  `json:"toAccountId"`
-	Message       string  `json:"message"`
-	Amount        float32 `json:"amount"`
+	FromAccountID string `json:"fromAccountId"`
+	ToAccountID   string `json:"toAccountId"`
+	Message       string `json:"message"`
+	Amount        Money  `json:"amount"`
 }
 
 // Transfer  executes a transfer between two accounts.
@@ -29,31 +29,13 @@ func (c *Client) Transfer(ctx context.Context, q *TransferQuery) error {
 		return fmt.Errorf("Marshal: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/v1/Transfers", c.bankBaseURL)
+	url := fmt.Sprintf("%s/%s/Transfers", c.bankBaseURL, c.apiVersion)
 
-	res, sc, err := c.transport.Request(ctx, &transport.HTTPRequest{
+	_, err = do[struct{}](ctx, c, "Transfer", &transport.HTTPRequest{
 		Method:      http.MethodPost,
 		URL:         url,
 		PostPayload: payload,
 	})
-	if err != nil {
-		return fmt.Errorf("request: %w", err)
-	}
-
-	var data transport.HTTPResponse
-	if err := json.Unmarshal(res, &data); err != nil {
-		return fmt.Errorf("Unmarshal: %w", err)
-	}
-
-	if data.IsError || sc != http.StatusOK {
-		return &Error{
-			"Transfer",
-			data.ErrorType,
-			data.ErrorMessage,
-			data.ErrorCode,
-			sc,
-		}
-	}
 
-	return nil
+	return err
 }
@@ -59,7 +59,7 @@ func TestTransfer(t *testing.T) {
 				FromAccountID: "133713371337",
 				ToAccountID:   "leetleetleet",
 				Message:       "transfer",
-				Amount:        1337.13,
+				Amount:        133713,
 			},
 			behavior: "fail",
 			exp:      getTestError("Transfer"),
@@ -70,7 +70,7 @@ func TestTransfer(t *testing.T) {
 				FromAccountID: "133713371337",
 				ToAccountID:   "leetleetleet",
 				Message:       "transfer",
-				Amount:        1337.13,
+				Amount:        133713,
 			},
 			exp: nil,
 		},